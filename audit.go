@@ -0,0 +1,164 @@
+package main
+
+import (
+	"fmt"
+	"github.com/iancoleman/strcase"
+	"strings"
+)
+
+const (
+	AuditCreated   = "created"
+	AuditUpdated   = "updated"
+	AuditDeleted   = "deleted"
+	AuditCreatedBy = "createdBy"
+	AuditVersion   = "version"
+)
+
+// AuditColumns maps audit roles to column names.
+type AuditColumns struct {
+	// declared is true if audit columns are set by option.
+	declared     bool
+	columnByRole map[string]string
+}
+
+// newAuditColumns parses audit columns. e.g. "created:created_at,updated:updated_at,version:version"
+// created_at and updated_at are used if audit is empty.
+func newAuditColumns(audit string) (*AuditColumns, error) {
+	columnByRole := map[string]string{
+		AuditCreated: "created_at",
+		AuditUpdated: "updated_at",
+	}
+	if audit == "" {
+		return &AuditColumns{columnByRole: columnByRole}, nil
+	}
+
+	columnByRole = make(map[string]string)
+	roleByColumn := make(map[string]string)
+	for _, token := range strings.Split(audit, ",") {
+		kv := strings.SplitN(strings.TrimSpace(token), ":", 2)
+		if len(kv) != 2 || kv[1] == "" {
+			return nil, fmt.Errorf("invalid audit column: %s", token)
+		}
+		role, column := kv[0], kv[1]
+		switch role {
+		case AuditCreated, AuditUpdated, AuditDeleted, AuditCreatedBy, AuditVersion:
+		default:
+			return nil, fmt.Errorf("unsupported audit role: %s", role)
+		}
+		if _, ok := columnByRole[role]; ok {
+			return nil, fmt.Errorf("duplicate audit role: %s", role)
+		}
+		// column names are compared in snake case
+		if other, ok := roleByColumn[strcase.ToSnake(column)]; ok {
+			return nil, fmt.Errorf("duplicate audit column: %s of %s and %s", column, other, role)
+		}
+		columnByRole[role] = column
+		roleByColumn[strcase.ToSnake(column)] = role
+	}
+	return &AuditColumns{declared: true, columnByRole: columnByRole}, nil
+}
+
+// GetRole returns audit role of the column. empty string is returned if column is not an audit column.
+// column names are compared in snake case, so 'createdAt' matches 'created_at'.
+func (a *AuditColumns) GetRole(column *Column) string {
+	name := strcase.ToSnake(column.Name)
+	for role, columnName := range a.columnByRole {
+		if strcase.ToSnake(columnName) == name {
+			return role
+		}
+	}
+	return ""
+}
+
+// GetColumn returns column of the audit role.
+func (a *AuditColumns) GetColumn(table *Table, role string) *Column {
+	for _, column := range table.Columns {
+		if a.GetRole(column) == role {
+			return column
+		}
+	}
+	return nil
+}
+
+// jpaClassAnnotations returns entity annotations and imports for audit columns.
+// classSuffix is '::class' for kotlin, and '.class' for java.
+// @SQLRestriction is used instead of deprecated @Where with jakarta persistence(Hibernate 6).
+func (a *AuditColumns) jpaClassAnnotations(table *Table, classSuffix string, persistence string) ([]string, []string) {
+	annotations := make([]string, 0)
+	importSet := NewStringSet()
+	if !a.declared {
+		return annotations, importSet.Slice()
+	}
+
+	// spring data auditing
+	for _, role := range []string{AuditCreated, AuditUpdated, AuditCreatedBy} {
+		if a.GetColumn(table, role) != nil {
+			annotations = append(annotations, fmt.Sprintf("@EntityListeners(AuditingEntityListener%s)", classSuffix))
+			importSet.Add("org.springframework.data.jpa.domain.support.AuditingEntityListener")
+			break
+		}
+	}
+
+	// soft delete
+	deleted := a.GetColumn(table, AuditDeleted)
+	pkColumns := make([]*Column, 0)
+	for _, column := range table.Columns {
+		if column.PrimaryKey {
+			pkColumns = append(pkColumns, column)
+		}
+	}
+	if deleted != nil && len(pkColumns) == 1 {
+		setValue, clause := "CURRENT_TIMESTAMP", deleted.Name+" IS NULL"
+		if deleted.Type == ColTypeBoolean {
+			setValue, clause = "true", deleted.Name+" = false"
+		}
+		where := pkColumns[0].Name + " = ?"
+		if version := a.GetColumn(table, AuditVersion); version != nil {
+			where += " AND " + version.Name + " = ?"
+		}
+		annotations = append(annotations,
+			fmt.Sprintf("@SQLDelete(sql = \"UPDATE %s SET %s = %s WHERE %s\")", table.Name, deleted.Name, setValue, where))
+		importSet.Add("org.hibernate.annotations.SQLDelete")
+		if persistence == JpaPersistenceJakarta {
+			annotations = append(annotations, fmt.Sprintf("@SQLRestriction(\"%s\")", clause))
+			importSet.Add("org.hibernate.annotations.SQLRestriction")
+		} else {
+			annotations = append(annotations, fmt.Sprintf("@Where(clause = \"%s\")", clause))
+			importSet.Add("org.hibernate.annotations.Where")
+		}
+	}
+	return annotations, importSet.Slice()
+}
+
+// jpaFieldAnnotations returns field annotations and imports for the audit column.
+// notUpdatable is true if the column should not be updated.
+func (a *AuditColumns) jpaFieldAnnotations(column *Column) (annotations []string, notUpdatable bool, imports []string) {
+	role := a.GetRole(column)
+
+	// hibernate timestamps are used if audit columns are not declared
+	if !a.declared {
+		if column.Type != ColTypeDateTime {
+			return nil, false, nil
+		}
+		switch role {
+		case AuditCreated:
+			return []string{"@CreationTimestamp"}, true, []string{"org.hibernate.annotations.CreationTimestamp"}
+		case AuditUpdated:
+			return []string{"@UpdateTimestamp"}, false, []string{"org.hibernate.annotations.UpdateTimestamp"}
+		}
+		return nil, false, nil
+	}
+
+	switch role {
+	case AuditCreated:
+		return []string{"@CreatedDate"}, true, []string{"org.springframework.data.annotation.CreatedDate"}
+	case AuditUpdated:
+		return []string{"@LastModifiedDate"}, false, []string{"org.springframework.data.annotation.LastModifiedDate"}
+	case AuditCreatedBy:
+		return []string{"@CreatedBy"}, true, []string{"org.springframework.data.annotation.CreatedBy"}
+	case AuditVersion:
+		// javax/jakarta.persistence.Version
+		return []string{"@Version"}, false, nil
+	}
+	return nil, false, nil
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"strings"
+	"testing"
+)
+
+func TestNewAuditColumns(t *testing.T) {
+	cases := []struct {
+		audit    string
+		expected map[string]string
+		err      string
+	}{
+		{
+			audit:    "",
+			expected: map[string]string{AuditCreated: "created_at", AuditUpdated: "updated_at"},
+		},
+		{
+			audit: "created:createdAt, updated:updatedAt,deleted:deleted_at,createdBy:created_by,version:version",
+			expected: map[string]string{
+				AuditCreated:   "createdAt",
+				AuditUpdated:   "updatedAt",
+				AuditDeleted:   "deleted_at",
+				AuditCreatedBy: "created_by",
+				AuditVersion:   "version",
+			},
+		},
+		{audit: "created", err: "invalid audit column: created"},
+		{audit: "created:", err: "invalid audit column: created:"},
+		{audit: "removed:removed_at", err: "unsupported audit role: removed"},
+		{audit: "created:created_at,created:inserted_at", err: "duplicate audit role: created"},
+		{audit: "created:updated_at,updated:updatedAt", err: "duplicate audit column: updatedAt of created and updated"},
+	}
+
+	for _, c := range cases {
+		audit, err := newAuditColumns(c.audit)
+		if c.err != "" {
+			if err == nil || err.Error() != c.err {
+				t.Errorf("TestNewAuditColumns(%s) expected error '%s', got %v", c.audit, c.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("TestNewAuditColumns(%s) unexpected error: %v", c.audit, err)
+			continue
+		}
+		if diff := cmp.Diff(c.expected, audit.columnByRole); diff != "" {
+			t.Errorf("TestNewAuditColumns(%s) mismatch (-expected +actual):\n%s", c.audit, diff)
+		}
+		if audit.declared != (c.audit != "") {
+			t.Errorf("TestNewAuditColumns(%s) unexpected declared: %v", c.audit, audit.declared)
+		}
+	}
+}
+
+func TestAuditColumns_GetRole(t *testing.T) {
+	audit, err := newAuditColumns("created:created_at,updated:updatedAt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"created_at": AuditCreated,
+		"createdAt":  AuditCreated,
+		"updated_at": AuditUpdated,
+		"updatedAt":  AuditUpdated,
+		"created":    "",
+		"deleted_at": "",
+	}
+	for name, role := range expected {
+		if actual := audit.GetRole(&Column{Name: name}); actual != role {
+			t.Errorf("TestAuditColumns_GetRole(%s) expected '%s', got '%s'", name, role, actual)
+		}
+	}
+}
+
+func TestAuditColumns_jpaClassAnnotations(t *testing.T) {
+	table := &Table{
+		Name: "user",
+		Columns: []*Column{
+			{Name: "id", Type: ColTypeLong, PrimaryKey: true},
+			{Name: "created_at", Type: ColTypeDateTime},
+			{Name: "deleted_at", Type: ColTypeDateTime, Nullable: true},
+			{Name: "version", Type: ColTypeLong},
+		},
+	}
+	audit, err := newAuditColumns("created:created_at,deleted:deleted_at,version:version")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	annotations, imports := audit.jpaClassAnnotations(table, "::class", JpaPersistenceJavax)
+	expected := []string{
+		"@EntityListeners(AuditingEntityListener::class)",
+		"@SQLDelete(sql = \"UPDATE user SET deleted_at = CURRENT_TIMESTAMP WHERE id = ? AND version = ?\")",
+		"@Where(clause = \"deleted_at IS NULL\")",
+	}
+	if diff := cmp.Diff(expected, annotations); diff != "" {
+		t.Errorf("TestAuditColumns_jpaClassAnnotations() mismatch (-expected +actual):\n%s", diff)
+	}
+	expectedImports := []string{
+		"org.hibernate.annotations.SQLDelete",
+		"org.hibernate.annotations.Where",
+		"org.springframework.data.jpa.domain.support.AuditingEntityListener",
+	}
+	if diff := cmp.Diff(expectedImports, imports); diff != "" {
+		t.Errorf("TestAuditColumns_jpaClassAnnotations() imports mismatch (-expected +actual):\n%s", diff)
+	}
+
+	// jakarta, boolean deleted column
+	table.Columns[2] = &Column{Name: "deleted_at", Type: ColTypeBoolean}
+	annotations, imports = audit.jpaClassAnnotations(table, ".class", JpaPersistenceJakarta)
+	expected = []string{
+		"@EntityListeners(AuditingEntityListener.class)",
+		"@SQLDelete(sql = \"UPDATE user SET deleted_at = true WHERE id = ? AND version = ?\")",
+		"@SQLRestriction(\"deleted_at = false\")",
+	}
+	if diff := cmp.Diff(expected, annotations); diff != "" {
+		t.Errorf("TestAuditColumns_jpaClassAnnotations() jakarta mismatch (-expected +actual):\n%s", diff)
+	}
+	if strings.Contains(strings.Join(imports, ","), "Where") {
+		t.Errorf("TestAuditColumns_jpaClassAnnotations() unexpected import: %v", imports)
+	}
+
+	// soft delete is not supported for composite primary key
+	table.Columns[1].PrimaryKey = true
+	annotations, _ = audit.jpaClassAnnotations(table, "::class", JpaPersistenceJavax)
+	if diff := cmp.Diff([]string{"@EntityListeners(AuditingEntityListener::class)"}, annotations); diff != "" {
+		t.Errorf("TestAuditColumns_jpaClassAnnotations() composite key mismatch (-expected +actual):\n%s", diff)
+	}
+}
+
+func TestAuditColumns_jpaFieldAnnotations(t *testing.T) {
+	legacy, _ := newAuditColumns("")
+	declared, _ := newAuditColumns("created:created_at,updated:updated_at,createdBy:created_by,version:version")
+
+	cases := []struct {
+		audit        *AuditColumns
+		column       *Column
+		annotations  []string
+		notUpdatable bool
+	}{
+		{legacy, &Column{Name: "created_at", Type: ColTypeDateTime}, []string{"@CreationTimestamp"}, true},
+		{legacy, &Column{Name: "updated_at", Type: ColTypeDateTime}, []string{"@UpdateTimestamp"}, false},
+		{legacy, &Column{Name: "created_at", Type: ColTypeLong}, nil, false},
+		{declared, &Column{Name: "created_at", Type: ColTypeDateTime}, []string{"@CreatedDate"}, true},
+		{declared, &Column{Name: "updated_at", Type: ColTypeDateTime}, []string{"@LastModifiedDate"}, false},
+		{declared, &Column{Name: "created_by", Type: ColTypeString}, []string{"@CreatedBy"}, true},
+		{declared, &Column{Name: "version", Type: ColTypeLong}, []string{"@Version"}, false},
+		{declared, &Column{Name: "name", Type: ColTypeString}, nil, false},
+	}
+	for _, c := range cases {
+		annotations, notUpdatable, _ := c.audit.jpaFieldAnnotations(c.column)
+		if diff := cmp.Diff(c.annotations, annotations); diff != "" || notUpdatable != c.notUpdatable {
+			t.Errorf("TestAuditColumns_jpaFieldAnnotations(%s) mismatch. notUpdatable: %v (-expected +actual):\n%s",
+				c.column.Name, notUpdatable, diff)
+		}
+	}
+}
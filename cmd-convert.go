@@ -49,6 +49,18 @@ func (cmd *ConvertCmd) schemaToOutput(schema *Schema, output *Output) error {
 		return schema.ToFile(output.FilePath)
 	case FormatDbdiagramIo:
 		writer = &DBDiagramIO{}
+	case FormatDot:
+		writer = &Graphviz{
+			TableFilterFn: getTableFilterFn(output.Get(FlagGroups)),
+		}
+	case FormatDrawio:
+		writer = &DrawIO{
+			TableFilterFn: getTableFilterFn(output.Get(FlagGroups)),
+		}
+	case FormatMermaid:
+		writer = &Mermaid{
+			TableFilterFn: getTableFilterFn(output.Get(FlagGroups)),
+		}
 	case FormatPlantuml:
 		writer = &PlantUML{}
 	case FormatQuickdbd:
@@ -59,6 +71,12 @@ func (cmd *ConvertCmd) schemaToOutput(schema *Schema, output *Output) error {
 		}
 	case FormatSqlMysql:
 		writer = &Mysql{}
+	case FormatStaruml2:
+		writer = &StarUML2{}
+	case FormatSvg:
+		writer = &SVG{
+			TableFilterFn: getTableFilterFn(output.Get(FlagGroups)),
+		}
 	}
 
 	if writer == nil {
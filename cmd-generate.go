@@ -15,9 +15,13 @@ func (cmd *GenerateCmd) Generate(input *Input, output *Output) error {
 	if err != nil {
 		return err
 	}
+	return cmd.generate(schema, output)
+}
 
+// generate writes schema to output format.
+func (cmd *GenerateCmd) generate(schema *Schema, output *Output) error {
 	// table filter
-	tableFilterFn := cmd.getTableFilterFn(output.Get(FlagGroups))
+	tableFilterFn := getTableFilterFn(output.Get(FlagGroups))
 
 	// annotation mapper
 	annoMapper := newAnnotationMapper(output.Get(FlagAnnotation))
@@ -25,12 +29,18 @@ func (cmd *GenerateCmd) Generate(input *Input, output *Output) error {
 	prefixMapper := newPrefixMapper(output.Get(FlagPrefix))
 
 	switch output.Format {
+	case FormatExposed:
+		exposed := &Exposed{}
+		return exposed.Generate(schema, output, tableFilterFn, prefixMapper)
 	case FormatGorm:
 		gorm := &Gorm{}
 		return gorm.Generate(schema, output, tableFilterFn, prefixMapper)
 	case FormatGraphql:
 		graphql := &Graphql{}
 		return graphql.Generate(schema, output, tableFilterFn, prefixMapper)
+	case FormatJpaJava:
+		jpa := &JPAJava{}
+		return jpa.Generate(schema, output, tableFilterFn, annoMapper, prefixMapper)
 	case FormatJpaKotlin:
 		jpa := &JPAKotlin{}
 		return jpa.Generate(schema, output, tableFilterFn, annoMapper, prefixMapper, false)
@@ -48,7 +58,7 @@ func (cmd *GenerateCmd) Generate(input *Input, output *Output) error {
 	return fmt.Errorf("unsupported output format: %s", output.Format)
 }
 
-func (cmd *GenerateCmd) getTableFilterFn(groups string) TableFilterFn {
+func getTableFilterFn(groups string) TableFilterFn {
 	if groups == "" {
 		return nil
 	}
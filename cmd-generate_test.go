@@ -0,0 +1,72 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// generateFiles runs generate with output directory and options,
+// and returns contents of generated files by relative path.
+func generateFiles(t *testing.T, options map[string]string, generate func(output *Output) error) (map[string]string, error) {
+	dir, err := ioutil.TempDir("", "generate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := generate(&Output{FilePath: dir, Options: options}); err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]string)
+	err = filepath.Walk(dir, func(filename string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		data, err := ioutil.ReadFile(filename)
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(dir, filename)
+		if err != nil {
+			return err
+		}
+		result[filepath.ToSlash(relPath)] = string(data)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result, nil
+}
+
+// generateOutput generates schema to output format and returns contents of generated files by relative path.
+// output.FilePath is relative to output directory. e.g. "models.go" to generate a single file.
+func generateOutput(t *testing.T, schema *Schema, output *Output) (map[string]string, error) {
+	return generateFiles(t, output.Options, func(tempOutput *Output) error {
+		tempOutput.FilePath = path.Join(tempOutput.FilePath, output.FilePath)
+		tempOutput.Format = output.Format
+		cmd := &GenerateCmd{}
+		return cmd.generate(schema, tempOutput)
+	})
+}
+
+// assertContains checks if text contains all lines in order.
+func assertContains(t *testing.T, name string, text string, lines ...string) {
+	t.Helper()
+	expected := strings.Join(lines, "\n")
+	if !strings.Contains(text, expected) {
+		t.Errorf("%s: expected\n%s\nnot found in\n%s", name, expected, text)
+	}
+}
+
+func TestGenerateCmd_generate_UnsupportedFormat(t *testing.T) {
+	_, err := generateOutput(t, &Schema{}, &Output{Format: "unknown"})
+	if err == nil || err.Error() != "unsupported output format: unknown" {
+		t.Errorf("TestGenerateCmd_generate_UnsupportedFormat() unexpected error: %v", err)
+	}
+}
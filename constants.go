@@ -2,11 +2,16 @@ package main
 
 const (
 	FormatDbdiagramIo     = "dbdiagram.io"
+	FormatDot             = "dot"
+	FormatDrawio          = "drawio"
+	FormatExposed         = "exposed"
 	FormatGorm            = "gorm"
 	FormatGraphql         = "graphql"
+	FormatJpaJava         = "jpa-java"
 	FormatJpaKotlin       = "jpa-kotlin"
 	FormatJpaKotlinData   = "jpa-kotlin-data"
 	FormatLiquibase       = "liquibase"
+	FormatMermaid         = "mermaid"
 	FormatOctopus         = "octopus"
 	FormatOptiStudio      = "opti-studio"
 	FormatPlantuml        = "plantuml"
@@ -20,6 +25,7 @@ const (
 	FormatSqlSqlite3      = "sqlite3"
 	FormatSqlSqlserver    = "sqlserver"
 	FormatStaruml2        = "staruml2"
+	FormatSvg             = "svg"
 	FormatXlsx            = "xlsx"
 
 	ColTypeBlob     = "blob"
@@ -36,18 +42,34 @@ const (
 	ColTypeTime     = "time"
 
 	FlagAnnotation            = "annotation"
+	FlagAudit                 = "audit"
+	FlagChangelogFormat       = "changelogFormat"
+	FlagChangeSetId           = "changeSetId"
+	FlagDao                   = "dao"
+	FlagDbms                  = "dbms"
+	FlagCascade               = "cascade"
 	FlagDiff                  = "diff"
+	FlagFetch                 = "fetch"
+	FlagGraphqlFramework      = "graphqlFramework"
 	FlagGraphqlPackage        = "graphqlPackage"
 	FlagGroups                = "groups"
 	FlagGormModel             = "gormModel"
+	FlagGormVersion           = "gormVersion"
 	FlagIdEntity              = "idEntity"
+	FlagMasterChangelog       = "masterChangelog"
+	FlagLombok                = "lombok"
+	FlagManyToMany            = "manyToMany"
 	FlagNotNull               = "notNull"
+	FlagOneToMany             = "oneToMany"
 	FlagPackage               = "package"
+	FlagPersistence           = "persistence"
 	FlagPrefix                = "prefix"
 	FlagRelation              = "relation"
 	FlagRemovePrefix          = "removePrefix"
 	FlagReposPackage          = "reposPackage"
 	FlagSourceFormat          = "sourceFormat"
+	FlagSplit                 = "split"
+	FlagTableOptions          = "tableOptions"
 	FlagTargetFormat          = "targetFormat"
 	FlagUniqueNameSuffix      = "uniqueNameSuffix"
 	FlagUseComments           = "comments"
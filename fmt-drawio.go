@@ -0,0 +1,306 @@
+package main
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+)
+
+const (
+	drawioMargin       = 40
+	drawioGap          = 80
+	drawioHeaderHeight = 30
+	drawioRowHeight    = 26
+	drawioKeyWidth     = 36
+	drawioCharWidth    = 8
+	drawioMinWidth     = 160
+
+	drawioTableStyle = "shape=table;startSize=30;container=1;collapsible=1;childLayout=tableLayout;fixedRows=1;rowLines=0;fontStyle=1;align=center;resizeLast=1;html=1;"
+	drawioRowStyle   = "shape=tableRow;horizontal=0;startSize=0;swimlaneHead=0;swimlaneBody=0;fillColor=none;collapsible=0;dropTarget=0;points=[[0,0.5],[1,0.5]];portConstraint=eastwest;top=0;left=0;right=0;bottom=0;html=1;"
+	drawioKeyStyle   = "shape=partialRectangle;connectable=0;fillColor=none;top=0;left=0;bottom=0;right=0;fontStyle=1;overflow=hidden;html=1;"
+	drawioNameStyle  = "shape=partialRectangle;connectable=0;fillColor=none;top=0;left=0;bottom=0;right=0;align=left;spacingLeft=6;overflow=hidden;html=1;"
+	drawioEdgeStyle  = "edgeStyle=entityRelationEdgeStyle;fontSize=12;html=1;"
+)
+
+type DrawIO struct {
+	TableFilterFn TableFilterFn
+}
+
+type drawioFile struct {
+	XMLName  xml.Name         `xml:"mxfile"`
+	Host     string           `xml:"host,attr"`
+	Diagrams []*drawioDiagram `xml:"diagram"`
+}
+
+type drawioDiagram struct {
+	ID         string            `xml:"id,attr"`
+	Name       string            `xml:"name,attr"`
+	GraphModel *drawioGraphModel `xml:"mxGraphModel"`
+}
+
+type drawioGraphModel struct {
+	Grid     int           `xml:"grid,attr"`
+	GridSize int           `xml:"gridSize,attr"`
+	Guides   int           `xml:"guides,attr"`
+	Connect  int           `xml:"connect,attr"`
+	Arrows   int           `xml:"arrows,attr"`
+	Page     int           `xml:"page,attr"`
+	Cells    []*drawioCell `xml:"root>mxCell"`
+}
+
+type drawioCell struct {
+	ID       string          `xml:"id,attr"`
+	Value    *string         `xml:"value,attr"`
+	Style    string          `xml:"style,attr,omitempty"`
+	Vertex   string          `xml:"vertex,attr,omitempty"`
+	Edge     string          `xml:"edge,attr,omitempty"`
+	Parent   string          `xml:"parent,attr,omitempty"`
+	Source   string          `xml:"source,attr,omitempty"`
+	Target   string          `xml:"target,attr,omitempty"`
+	Geometry *drawioGeometry `xml:"mxGeometry"`
+}
+
+type drawioGeometry struct {
+	X        int    `xml:"x,attr,omitempty"`
+	Y        int    `xml:"y,attr,omitempty"`
+	Width    int    `xml:"width,attr,omitempty"`
+	Height   int    `xml:"height,attr,omitempty"`
+	Relative string `xml:"relative,attr,omitempty"`
+	As       string `xml:"as,attr"`
+}
+
+func (f *DrawIO) FromFile(filename string) error {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+
+	return f.FromString(data)
+}
+
+func (f *DrawIO) FromString(data []byte) error {
+	// TODO
+	return errors.New("not implemented")
+}
+
+func (f *DrawIO) ToSchema() (*Schema, error) {
+	// TODO
+	return nil, errors.New("not implemented")
+}
+
+func (f *DrawIO) ToFile(schema *Schema, filename string) error {
+	data, err := f.ToString(schema)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(filename, data, 0644)
+}
+
+func (f *DrawIO) ToString(schema *Schema) ([]byte, error) {
+	file := &drawioFile{
+		Host:     "octopus-db-tools",
+		Diagrams: make([]*drawioDiagram, 0),
+	}
+
+	for _, group := range schema.Groups() {
+		tables := make([]*Table, 0)
+		for _, table := range schema.Tables {
+			// filter table
+			if f.TableFilterFn != nil && !f.TableFilterFn(table) {
+				continue
+			}
+			if table.Group == group {
+				tables = append(tables, table)
+			}
+		}
+		if len(tables) == 0 {
+			continue
+		}
+
+		file.Diagrams = append(file.Diagrams, &drawioDiagram{
+			ID:   fmt.Sprintf("page-%d", len(file.Diagrams)+1),
+			Name: TernaryString(group == "", "Tables", group),
+			GraphModel: &drawioGraphModel{
+				Grid:     1,
+				GridSize: 10,
+				Guides:   1,
+				Connect:  1,
+				Arrows:   1,
+				Page:     1,
+				Cells:    f.getCells(tables),
+			},
+		})
+	}
+
+	data, err := xml.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// getCells returns table, row and connector cells of a page.
+func (f *DrawIO) getCells(tables []*Table) []*drawioCell {
+	cells := []*drawioCell{
+		{ID: "0"},
+		{ID: "1", Parent: "0"},
+	}
+
+	// row cell ID by 'table.column'
+	rowIdByName := make(map[string]string)
+	tableIdByName := make(map[string]string)
+
+	columnCount := int(math.Ceil(math.Sqrt(float64(len(tables)))))
+	x, y := drawioMargin, drawioMargin
+	rowMaxHeight := 0
+
+	for i, table := range tables {
+		if i > 0 && i%columnCount == 0 {
+			x = drawioMargin
+			y += rowMaxHeight + drawioGap
+			rowMaxHeight = 0
+		}
+
+		maxTextLen := len(table.Name)
+		for _, column := range table.Columns {
+			if textLen := len(f.getColumnText(column)); textLen > maxTextLen {
+				maxTextLen = textLen
+			}
+		}
+		width := drawioKeyWidth + maxTextLen*drawioCharWidth + 20
+		if width < drawioMinWidth {
+			width = drawioMinWidth
+		}
+		height := drawioHeaderHeight + len(table.Columns)*drawioRowHeight
+
+		tableId := fmt.Sprintf("t%d", i+1)
+		tableIdByName[table.Name] = tableId
+		cells = append(cells, &drawioCell{
+			ID:       tableId,
+			Value:    f.value(table.Name),
+			Style:    drawioTableStyle,
+			Vertex:   "1",
+			Parent:   "1",
+			Geometry: &drawioGeometry{X: x, Y: y, Width: width, Height: height, As: "geometry"},
+		})
+
+		for j, column := range table.Columns {
+			rowId := fmt.Sprintf("%s-r%d", tableId, j+1)
+			rowIdByName[table.Name+"."+column.Name] = rowId
+
+			nameStyle := drawioNameStyle
+			if column.PrimaryKey {
+				nameStyle += "fontStyle=5;"
+			}
+
+			cells = append(cells,
+				&drawioCell{
+					ID:     rowId,
+					Value:  f.value(""),
+					Style:  drawioRowStyle,
+					Vertex: "1",
+					Parent: tableId,
+					Geometry: &drawioGeometry{
+						Y: drawioHeaderHeight + j*drawioRowHeight, Width: width, Height: drawioRowHeight, As: "geometry",
+					},
+				},
+				&drawioCell{
+					ID:       rowId + "-k",
+					Value:    f.value(f.getKeyText(column)),
+					Style:    drawioKeyStyle,
+					Vertex:   "1",
+					Parent:   rowId,
+					Geometry: &drawioGeometry{Width: drawioKeyWidth, Height: drawioRowHeight, As: "geometry"},
+				},
+				&drawioCell{
+					ID:     rowId + "-n",
+					Value:  f.value(f.getColumnText(column)),
+					Style:  nameStyle,
+					Vertex: "1",
+					Parent: rowId,
+					Geometry: &drawioGeometry{
+						X: drawioKeyWidth, Width: width - drawioKeyWidth, Height: drawioRowHeight, As: "geometry",
+					},
+				},
+			)
+		}
+
+		x += width + drawioGap
+		if height > rowMaxHeight {
+			rowMaxHeight = height
+		}
+	}
+
+	// connectors
+	edgeIndex := 0
+	for _, table := range tables {
+		for _, column := range table.Columns {
+			ref := column.Ref
+			if ref == nil {
+				continue
+			}
+			target, ok := rowIdByName[ref.Table+"."+ref.Column]
+			if !ok {
+				if target, ok = tableIdByName[ref.Table]; !ok {
+					// referenced table is on another page
+					continue
+				}
+			}
+
+			edgeIndex++
+			startArrow := TernaryString(column.UniqueKey, "ERzeroToOne", "ERzeroToMany")
+			endArrow := TernaryString(column.Nullable, "ERzeroToOne", "ERmandOne")
+			cells = append(cells, &drawioCell{
+				ID:     fmt.Sprintf("e%d", edgeIndex),
+				Value:  f.value(""),
+				Style:  fmt.Sprintf("%sstartArrow=%s;endArrow=%s;", drawioEdgeStyle, startArrow, endArrow),
+				Edge:   "1",
+				Parent: "1",
+				Source: rowIdByName[table.Name+"."+column.Name],
+				Target: target,
+				Geometry: &drawioGeometry{
+					Relative: "1", As: "geometry",
+				},
+			})
+		}
+	}
+
+	return cells
+}
+
+func (f *DrawIO) getKeyText(col *Column) string {
+	switch {
+	case col.PrimaryKey && col.Ref != nil:
+		return "PK,FK"
+	case col.PrimaryKey:
+		return "PK"
+	case col.Ref != nil:
+		return "FK"
+	case col.UniqueKey:
+		return "UK"
+	default:
+		return ""
+	}
+}
+
+func (f *DrawIO) getColumnText(col *Column) string {
+	colType := col.Type
+	if col.Size > 0 {
+		if col.Scale > 0 {
+			colType = fmt.Sprintf("%s(%d,%d)", col.Type, col.Size, col.Scale)
+		} else {
+			colType = fmt.Sprintf("%s(%d)", col.Type, col.Size)
+		}
+	}
+	if col.Nullable {
+		colType += "?"
+	}
+	return fmt.Sprintf("%s: %s", col.Name, colType)
+}
+
+func (f *DrawIO) value(s string) *string {
+	return &s
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"encoding/xml"
+	"github.com/google/go-cmp/cmp"
+	"testing"
+)
+
+func TestDrawIO_ToString(t *testing.T) {
+	schema := &Schema{
+		Tables: []*Table{
+			{
+				Name:  "group",
+				Group: "common",
+				Columns: []*Column{
+					{Name: "id", Type: ColTypeLong, PrimaryKey: true},
+				},
+			},
+			{
+				Name:  "user",
+				Group: "user",
+				Columns: []*Column{
+					{Name: "id", Type: ColTypeLong, PrimaryKey: true},
+					{Name: "group_id", Type: ColTypeLong, Nullable: true, Ref: &Reference{Table: "group", Column: "id"}},
+					{Name: "parent_id", Type: ColTypeLong, UniqueKey: true, Ref: &Reference{Table: "user", Column: "id"}},
+				},
+			},
+		},
+	}
+
+	drawio := DrawIO{}
+	data, err := drawio.ToString(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file := drawioFile{}
+	if err := xml.Unmarshal(data, &file); err != nil {
+		t.Fatal(err)
+	}
+	if len(file.Diagrams) != 2 {
+		t.Fatalf("TestDrawIO_ToString() expected 2 pages, got %d", len(file.Diagrams))
+	}
+
+	// user page
+	page := file.Diagrams[1]
+	if page.ID != "page-2" || page.Name != "user" {
+		t.Errorf("TestDrawIO_ToString() unexpected page: %s, %s", page.ID, page.Name)
+	}
+	values := make([]string, 0)
+	edges := make([]*drawioCell, 0)
+	for _, cell := range page.GraphModel.Cells {
+		if cell.Edge == "1" {
+			edges = append(edges, cell)
+		} else if cell.Value != nil && *cell.Value != "" {
+			values = append(values, *cell.Value)
+		}
+	}
+
+	expectedValues := []string{
+		"user",
+		"PK", "id: long",
+		"FK", "group_id: long?",
+		"FK", "parent_id: long",
+	}
+	if diff := cmp.Diff(expectedValues, values); diff != "" {
+		t.Errorf("TestDrawIO_ToString() values mismatch (-expected +actual):\n%s", diff)
+	}
+
+	// reference to table on another page is not connected
+	if len(edges) != 1 {
+		t.Fatalf("TestDrawIO_ToString() expected 1 edge, got %d", len(edges))
+	}
+	expectedEdge := &drawioCell{
+		ID:       "e1",
+		Value:    drawio.value(""),
+		Style:    drawioEdgeStyle + "startArrow=ERzeroToOne;endArrow=ERmandOne;",
+		Edge:     "1",
+		Parent:   "1",
+		Source:   "t1-r3",
+		Target:   "t1-r1",
+		Geometry: &drawioGeometry{Relative: "1", As: "geometry"},
+	}
+	if diff := cmp.Diff(expectedEdge, edges[0]); diff != "" {
+		t.Errorf("TestDrawIO_ToString() edge mismatch (-expected +actual):\n%s", diff)
+	}
+}
+
+func TestDrawIO_ToString_Groups(t *testing.T) {
+	schema := &Schema{
+		Tables: []*Table{
+			{Name: "group", Group: "common"},
+			{Name: "user", Group: "user"},
+			{Name: "log", Group: "etc"},
+		},
+	}
+
+	drawio := DrawIO{TableFilterFn: getTableFilterFn("user,etc")}
+	data, err := drawio.ToString(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file := drawioFile{}
+	if err := xml.Unmarshal(data, &file); err != nil {
+		t.Fatal(err)
+	}
+	names := make([]string, 0)
+	for _, page := range file.Diagrams {
+		names = append(names, page.ID+":"+page.Name)
+	}
+	if diff := cmp.Diff([]string{"page-1:etc", "page-2:user"}, names); diff != "" {
+		t.Errorf("TestDrawIO_ToString_Groups() mismatch (-expected +actual):\n%s", diff)
+	}
+}
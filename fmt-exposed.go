@@ -0,0 +1,429 @@
+package main
+
+import (
+	"fmt"
+	"github.com/gertd/go-pluralize"
+	"github.com/iancoleman/strcase"
+	"log"
+	"path"
+	"strings"
+)
+
+type ExposedTable struct {
+	table *Table
+	// name of table object. e.g. Users
+	Name string
+	// name of DAO entity class. e.g. User
+	EntityName string
+	// id column of IdTable. nil if the table object extends Table.
+	IdColumn *ExposedColumn
+	Columns  []*ExposedColumn
+}
+
+type ExposedColumn struct {
+	Column *Column
+	Name   string
+	// kotlin type. e.g. Long, String
+	Type string
+}
+
+type Exposed struct {
+}
+
+func NewExposedTable(
+	table *Table,
+	output *Output,
+	prefixMapper *PrefixMapper,
+) *ExposedTable {
+	entityName := jpaClassName(table, output, prefixMapper)
+	name := pluralize.NewClient().Plural(entityName)
+	if name == entityName {
+		name = name + "Table"
+	}
+
+	columns := make([]*ExposedColumn, 0)
+	pkColumns := make([]*ExposedColumn, 0)
+	for _, column := range table.Columns {
+		exposedColumn := NewExposedColumn(column)
+		columns = append(columns, exposedColumn)
+		if column.PrimaryKey {
+			pkColumns = append(pkColumns, exposedColumn)
+		}
+	}
+
+	// IdTable is used for auto increment or uuid primary key
+	var idColumn *ExposedColumn
+	if len(pkColumns) == 1 {
+		pk := pkColumns[0]
+		isIntId := (pk.Type == "Long" || pk.Type == "Int") && pk.Column.AutoIncremental
+		if isIntId || pk.Type == "UUID" {
+			idColumn = pk
+			for i, column := range columns {
+				if column == pk {
+					columns = append(columns[:i], columns[i+1:]...)
+					break
+				}
+			}
+		}
+	}
+
+	return &ExposedTable{
+		table:      table,
+		Name:       name,
+		EntityName: entityName,
+		IdColumn:   idColumn,
+		Columns:    columns,
+	}
+}
+
+func NewExposedColumn(column *Column) *ExposedColumn {
+	var fieldType string
+	switch strings.ToLower(column.Type) {
+	case ColTypeString, ColTypeText, "json":
+		fieldType = "String"
+	case ColTypeBoolean:
+		fieldType = "Boolean"
+	case ColTypeLong:
+		fieldType = "Long"
+	case ColTypeInt:
+		fieldType = "Int"
+	case ColTypeDecimal:
+		fieldType = "BigDecimal"
+	case ColTypeFloat:
+		fieldType = "Float"
+	case ColTypeDouble:
+		fieldType = "Double"
+	case ColTypeDateTime:
+		fieldType = "LocalDateTime"
+	case ColTypeDate:
+		fieldType = "LocalDate"
+	case ColTypeTime:
+		fieldType = "LocalTime"
+	case ColTypeBlob:
+		fieldType = "ExposedBlob"
+	case "uuid":
+		fieldType = "UUID"
+	case "bit":
+		if column.Size == 1 {
+			fieldType = "Boolean"
+		} else {
+			fieldType = "ByteArray"
+		}
+	default:
+		log.Printf("unknown column type: '%s', column: %s", column.Type, column.Name)
+		fieldType = "String"
+	}
+
+	return &ExposedColumn{
+		Column: column,
+		Name:   strcase.ToLowerCamel(column.Name),
+		Type:   fieldType,
+	}
+}
+
+// idTableClass returns IdTable class and DAO entity class names. e.g. LongIdTable, LongEntity
+func (t *ExposedTable) idTableClass() (string, string) {
+	switch t.IdColumn.Type {
+	case "Long":
+		return "LongIdTable", "LongEntity"
+	case "Int":
+		return "IntIdTable", "IntEntity"
+	default:
+		return "UUIDTable", "UUIDEntity"
+	}
+}
+
+// definition returns column definition function. e.g. varchar("name", 40)
+func (c *ExposedColumn) definition() string {
+	column := c.Column
+	name := Quote(column.Name, "\"")
+	switch strings.ToLower(column.Type) {
+	case ColTypeString:
+		size := column.Size
+		if size == 0 {
+			size = 255
+		}
+		return fmt.Sprintf("varchar(%s, %d)", name, size)
+	case ColTypeText, "json":
+		return fmt.Sprintf("text(%s)", name)
+	case ColTypeBoolean:
+		return fmt.Sprintf("bool(%s)", name)
+	case ColTypeLong:
+		return fmt.Sprintf("long(%s)", name)
+	case ColTypeInt:
+		return fmt.Sprintf("integer(%s)", name)
+	case ColTypeDecimal:
+		precision := column.Size
+		if precision == 0 {
+			precision = 19
+		}
+		return fmt.Sprintf("decimal(%s, %d, %d)", name, precision, column.Scale)
+	case ColTypeFloat:
+		return fmt.Sprintf("float(%s)", name)
+	case ColTypeDouble:
+		return fmt.Sprintf("double(%s)", name)
+	case ColTypeDateTime:
+		return fmt.Sprintf("datetime(%s)", name)
+	case ColTypeDate:
+		return fmt.Sprintf("date(%s)", name)
+	case ColTypeTime:
+		return fmt.Sprintf("time(%s)", name)
+	case ColTypeBlob:
+		return fmt.Sprintf("blob(%s)", name)
+	case "uuid":
+		return fmt.Sprintf("uuid(%s)", name)
+	case "bit":
+		if column.Size == 1 {
+			return fmt.Sprintf("bool(%s)", name)
+		}
+		return fmt.Sprintf("binary(%s, %d)", name, column.Size)
+	}
+	return fmt.Sprintf("text(%s)", name)
+}
+
+// defaultValue returns default value expression. empty string is returned if not supported.
+func (c *ExposedColumn) defaultValue() string {
+	value := c.Column.DefaultValue
+	if value == "" {
+		return ""
+	}
+	switch c.Type {
+	case "String":
+		return fmt.Sprintf(".default(%s)", Quote(strings.ReplaceAll(value, "\"", "\\\""), "\""))
+	case "Boolean":
+		return fmt.Sprintf(".default(%t)", value == "true" || value == "1")
+	case "Long":
+		return fmt.Sprintf(".default(%sL)", value)
+	case "Int", "Double":
+		return fmt.Sprintf(".default(%s)", value)
+	case "Float":
+		return fmt.Sprintf(".default(%sF)", value)
+	case "BigDecimal":
+		return fmt.Sprintf(".default(BigDecimal(\"%s\"))", value)
+	case "LocalDateTime":
+		if upper := strings.ToUpper(value); strings.HasPrefix(upper, "CURRENT_TIMESTAMP") || strings.HasPrefix(upper, "NOW(") {
+			return ".defaultExpression(CurrentDateTime)"
+		}
+	}
+	log.Printf("default value is not supported. column: %s, default: %s", c.Column.Name, value)
+	return ""
+}
+
+func (e *Exposed) Generate(
+	schema *Schema,
+	output *Output,
+	tableFilterFn TableFilterFn,
+	prefixMapper *PrefixMapper,
+) error {
+	outputPackage := output.Get(FlagPackage)
+	uniqueNameSuffix := output.Get(FlagUniqueNameSuffix)
+	useDao := output.GetBool(FlagDao)
+
+	// directories and files are written in the same way as kotlin
+	k := &JPAKotlin{}
+	outputDir, err := k.mkdir(output.FilePath, outputPackage)
+	if err != nil {
+		return err
+	}
+
+	exposedTables := make([]*ExposedTable, 0)
+	exposedTableByName := make(map[string]*ExposedTable)
+	for _, table := range schema.Tables {
+		// filter table
+		if tableFilterFn != nil && !tableFilterFn(table) {
+			continue
+		}
+		exposedTable := NewExposedTable(table, output, prefixMapper)
+		exposedTables = append(exposedTables, exposedTable)
+		exposedTableByName[table.Name] = exposedTable
+	}
+
+	indent := strings.Repeat(" ", 4)
+
+	for _, exposedTable := range exposedTables {
+		table := exposedTable.table
+		lines := make([]string, 0)
+		appendLine := func(line string) {
+			lines = append(lines, line)
+		}
+		importSet := NewStringSet("org.jetbrains.exposed.sql.*")
+
+		// object
+		idColumn := exposedTable.IdColumn
+		if idColumn != nil {
+			idTableClass, _ := exposedTable.idTableClass()
+			importSet.Add("org.jetbrains.exposed.dao.id.*")
+			if idColumn.Column.Name == "id" {
+				appendLine(fmt.Sprintf("object %s : %s(\"%s\") {", exposedTable.Name, idTableClass, table.Name))
+			} else {
+				appendLine(fmt.Sprintf("object %s : %s(\"%s\", \"%s\") {", exposedTable.Name, idTableClass, table.Name, idColumn.Column.Name))
+			}
+		} else {
+			appendLine(fmt.Sprintf("object %s : Table(\"%s\") {", exposedTable.Name, table.Name))
+		}
+
+		// unique
+		uniqueColumns := make([]*ExposedColumn, 0)
+		for _, column := range exposedTable.Columns {
+			if column.Column.UniqueKey {
+				uniqueColumns = append(uniqueColumns, column)
+			}
+		}
+
+		// columns
+		pkNames := make([]string, 0)
+		for _, column := range exposedTable.Columns {
+			col := column.Column
+			definition := column.definition()
+
+			// reference
+			if ref := col.Ref; ref != nil {
+				refTable, ok := exposedTableByName[ref.Table]
+				if !ok {
+					log.Printf("Relation not found. %s::%s -> %s", table.Name, col.Name, ref.Table)
+				} else if refTable.IdColumn != nil && refTable.IdColumn.Column.Name == ref.Column {
+					if col.Nullable && !col.PrimaryKey {
+						definition = fmt.Sprintf("optReference(\"%s\", %s)", col.Name, refTable.Name)
+					} else {
+						definition = fmt.Sprintf("reference(\"%s\", %s)", col.Name, refTable.Name)
+					}
+				} else {
+					definition += fmt.Sprintf(".references(%s.%s)", refTable.Name, strcase.ToLowerCamel(ref.Column))
+				}
+			}
+			if col.AutoIncremental {
+				definition += ".autoIncrement()"
+			}
+			if col.UniqueKey && len(uniqueColumns) == 1 {
+				definition += ".uniqueIndex()"
+			}
+			if col.Nullable && !col.PrimaryKey && !strings.HasPrefix(definition, "optReference") {
+				definition += ".nullable()"
+			}
+			definition += column.defaultValue()
+
+			switch column.Type {
+			case "LocalDateTime", "LocalDate", "LocalTime":
+				importSet.Add("org.jetbrains.exposed.sql.javatime.*")
+			case "BigDecimal":
+				if col.DefaultValue != "" {
+					importSet.Add("java.math.BigDecimal")
+				}
+			}
+
+			appendLine(indent + fmt.Sprintf("val %s = %s", column.Name, definition))
+			if col.PrimaryKey {
+				pkNames = append(pkNames, column.Name)
+			}
+		}
+
+		// primary key of Table
+		if len(pkNames) > 0 {
+			appendLine("")
+			appendLine(indent + fmt.Sprintf("override val primaryKey = PrimaryKey(%s)", strings.Join(pkNames, ", ")))
+		}
+
+		// composite unique key and indices
+		initLines := make([]string, 0)
+		if len(uniqueColumns) > 1 {
+			names := make([]string, 0)
+			for _, column := range uniqueColumns {
+				names = append(names, column.Name)
+			}
+			initLines = append(initLines, fmt.Sprintf("uniqueIndex(\"%s\", %s)", table.Name+uniqueNameSuffix, strings.Join(names, ", ")))
+		}
+		columnNameByName := make(map[string]string)
+		if idColumn != nil {
+			columnNameByName[idColumn.Column.Name] = "id"
+		}
+		for _, column := range exposedTable.Columns {
+			columnNameByName[column.Column.Name] = column.Name
+		}
+		for _, index := range table.Indices {
+			names := make([]string, 0)
+			for _, columnName := range index.Columns {
+				if name, ok := columnNameByName[columnName]; ok {
+					names = append(names, name)
+				}
+			}
+			if len(names) == len(index.Columns) {
+				initLines = append(initLines, fmt.Sprintf("index(\"%s\", false, %s)", indexName(table, index), strings.Join(names, ", ")))
+			}
+		}
+		if len(initLines) > 0 {
+			appendLine("")
+			appendLine(indent + "init {")
+			for _, line := range initLines {
+				appendLine(indent + indent + line)
+			}
+			appendLine(indent + "}")
+		}
+		appendLine("}")
+
+		// DAO
+		if useDao {
+			if idColumn == nil {
+				log.Printf("DAO entity of %s is not generated. IdTable is required", table.Name)
+			} else {
+				appendLine("")
+				lines = append(lines, e.entityLines(exposedTable, exposedTableByName, indent)...)
+				importSet.Add("org.jetbrains.exposed.dao.*")
+				if idColumn.Type == "UUID" {
+					importSet.Add("java.util.UUID")
+				}
+			}
+		}
+		appendLine("")
+
+		contents := make([]string, 0)
+		if outputPackage != "" {
+			contents = append(contents, "package "+outputPackage, "")
+		}
+		for _, imp := range importSet.Slice() {
+			contents = append(contents, "import "+imp)
+		}
+		contents = append(contents, "")
+		contents = append(contents, lines...)
+
+		if err := k.writeLines(path.Join(outputDir, exposedTable.EntityName+".kt"), contents); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// entityLines returns DAO Entity and EntityClass.
+func (e *Exposed) entityLines(exposedTable *ExposedTable, exposedTableByName map[string]*ExposedTable, indent string) []string {
+	_, entityClass := exposedTable.idTableClass()
+	lines := []string{
+		fmt.Sprintf("class %s(id: EntityID<%s>) : %s(id) {", exposedTable.EntityName, exposedTable.IdColumn.Type, entityClass),
+		indent + fmt.Sprintf("companion object : %sClass<%s>(%s)", entityClass, exposedTable.EntityName, exposedTable.Name),
+		"",
+	}
+
+	propertyNameSet := NewStringSet()
+	for _, column := range exposedTable.Columns {
+		propertyNameSet.Add(column.Name)
+	}
+	for _, column := range exposedTable.Columns {
+		property := fmt.Sprintf("%s.%s", exposedTable.Name, column.Name)
+
+		// referenced entity
+		if ref := column.Column.Ref; ref != nil {
+			refTable, ok := exposedTableByName[ref.Table]
+			if ok && refTable.IdColumn != nil && refTable.IdColumn.Column.Name == ref.Column {
+				name := refFieldName(propertyNameSet, column.Column, refTable.EntityName)
+				propertyNameSet.Add(name)
+				if column.Column.Nullable && !column.Column.PrimaryKey {
+					lines = append(lines, indent+fmt.Sprintf("var %s by %s optionalReferencedOn %s", name, refTable.EntityName, property))
+				} else {
+					lines = append(lines, indent+fmt.Sprintf("var %s by %s referencedOn %s", name, refTable.EntityName, property))
+				}
+				continue
+			}
+		}
+		lines = append(lines, indent+fmt.Sprintf("var %s by %s", column.Name, property))
+	}
+	lines = append(lines, "}")
+	return lines
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"strings"
+	"testing"
+)
+
+func TestExposed_Generate(t *testing.T) {
+	schema := &Schema{
+		Tables: []*Table{
+			{
+				Name:  "tb_group",
+				Group: "common",
+				Columns: []*Column{
+					{Name: "id", Type: ColTypeLong, PrimaryKey: true, AutoIncremental: true},
+					{Name: "name", Type: ColTypeString, Size: 40, UniqueKey: true},
+				},
+			},
+			{
+				Name: "tb_user",
+				Columns: []*Column{
+					{Name: "id", Type: ColTypeLong, PrimaryKey: true, AutoIncremental: true},
+					{Name: "group_id", Type: ColTypeLong, Ref: &Reference{Table: "tb_group", Column: "id"}},
+					{Name: "parent_id", Type: ColTypeLong, Nullable: true, Ref: &Reference{Table: "tb_user", Column: "id"}},
+					{Name: "created_at", Type: ColTypeDateTime, DefaultValue: "CURRENT_TIMESTAMP"},
+				},
+			},
+			{
+				Name:      "tb_user_group",
+				ClassName: "Membership",
+				Columns: []*Column{
+					{Name: "user_id", Type: ColTypeLong, PrimaryKey: true, Ref: &Reference{Table: "tb_user", Column: "id"}},
+					{Name: "group_id", Type: ColTypeLong, PrimaryKey: true, Ref: &Reference{Table: "tb_group", Column: "id"}},
+				},
+			},
+			{
+				Name: "tb_token",
+				Columns: []*Column{
+					{Name: "id", Type: "uuid", PrimaryKey: true},
+					{Name: "user_id", Type: ColTypeLong, Ref: &Reference{Table: "tb_user", Column: "id"}},
+				},
+			},
+		},
+	}
+
+	files, err := generateOutput(t, schema, &Output{Format: FormatExposed, Options: map[string]string{
+		FlagPackage:      "com.example.table",
+		FlagRemovePrefix: "tb_",
+		FlagPrefix:       "common:C",
+		FlagDao:          "true",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"com/example/table/CGroup.kt": strings.Join([]string{
+			"package com.example.table",
+			"",
+			"import org.jetbrains.exposed.dao.*",
+			"import org.jetbrains.exposed.dao.id.*",
+			"import org.jetbrains.exposed.sql.*",
+			"",
+			"object CGroups : LongIdTable(\"tb_group\") {",
+			"    val name = varchar(\"name\", 40).uniqueIndex()",
+			"}",
+			"",
+			"class CGroup(id: EntityID<Long>) : LongEntity(id) {",
+			"    companion object : LongEntityClass<CGroup>(CGroups)",
+			"",
+			"    var name by CGroups.name",
+			"}",
+			"",
+		}, "\n"),
+		"com/example/table/User.kt": strings.Join([]string{
+			"package com.example.table",
+			"",
+			"import org.jetbrains.exposed.dao.*",
+			"import org.jetbrains.exposed.dao.id.*",
+			"import org.jetbrains.exposed.sql.*",
+			"import org.jetbrains.exposed.sql.javatime.*",
+			"",
+			"object Users : LongIdTable(\"tb_user\") {",
+			"    val groupId = reference(\"group_id\", CGroups)",
+			"    val parentId = optReference(\"parent_id\", Users)",
+			"    val createdAt = datetime(\"created_at\").defaultExpression(CurrentDateTime)",
+			"}",
+			"",
+			"class User(id: EntityID<Long>) : LongEntity(id) {",
+			"    companion object : LongEntityClass<User>(Users)",
+			"",
+			"    var cGroup by CGroup referencedOn Users.groupId",
+			"    var user by User optionalReferencedOn Users.parentId",
+			"    var createdAt by Users.createdAt",
+			"}",
+			"",
+		}, "\n"),
+		// DAO entity is not generated for composite primary key
+		"com/example/table/Membership.kt": strings.Join([]string{
+			"package com.example.table",
+			"",
+			"import org.jetbrains.exposed.sql.*",
+			"",
+			"object Memberships : Table(\"tb_user_group\") {",
+			"    val userId = reference(\"user_id\", Users)",
+			"    val groupId = reference(\"group_id\", CGroups)",
+			"",
+			"    override val primaryKey = PrimaryKey(userId, groupId)",
+			"}",
+			"",
+		}, "\n"),
+		"com/example/table/Token.kt": strings.Join([]string{
+			"package com.example.table",
+			"",
+			"import java.util.UUID",
+			"import org.jetbrains.exposed.dao.*",
+			"import org.jetbrains.exposed.dao.id.*",
+			"import org.jetbrains.exposed.sql.*",
+			"",
+			"object Tokens : UUIDTable(\"tb_token\") {",
+			"    val userId = reference(\"user_id\", Users)",
+			"}",
+			"",
+			"class Token(id: EntityID<UUID>) : UUIDEntity(id) {",
+			"    companion object : UUIDEntityClass<Token>(Tokens)",
+			"",
+			"    var user by User referencedOn Tokens.userId",
+			"}",
+			"",
+		}, "\n"),
+	}
+	if diff := cmp.Diff(expected, files); diff != "" {
+		t.Errorf("TestExposed_Generate() mismatch (-expected +actual):\n%s", diff)
+	}
+}
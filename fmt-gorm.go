@@ -11,13 +11,21 @@ import (
 	"strings"
 )
 
+const (
+	GormVersion1 = "v1"
+	GormVersion2 = "v2"
+)
+
 type GormClass struct {
 	table        *Table
 	Name         string
 	EmbedModel   bool
+	Imports      []string
 	Fields       []*GormField
 	PKFields     []*GormField
 	UniqueFields []*GormField
+	// embeds of embedded structs
+	Embeds []*Embed
 }
 
 type GormField struct {
@@ -26,6 +34,8 @@ type GormField struct {
 	Type         string
 	OverrideName bool
 	Imports      []string
+	// struct tags except gorm
+	Tags []string
 }
 
 type Gorm struct {
@@ -35,6 +45,18 @@ func getGormModelColumns() []string {
 	return []string{"id", "created_at", "updated_at", "deleted_at"}
 }
 
+func getGormVersion(output *Output) (string, error) {
+	version := strings.ToLower(output.Get(FlagGormVersion))
+	switch version {
+	case "":
+		return GormVersion1, nil
+	case GormVersion1, GormVersion2:
+		return version, nil
+	default:
+		return "", fmt.Errorf("unsupported GORM version: %s", version)
+	}
+}
+
 func NewGormClass(
 	table *Table,
 	output *Output,
@@ -52,12 +74,27 @@ func NewGormClass(
 			className = prefix + className
 		}
 	}
+	target := table.Target(FormatGorm)
+	if target.Name != "" {
+		className = target.Name
+	}
 
 	fields := make([]*GormField, 0)
 	pkFields := make([]*GormField, 0)
 	uniqueFields := make([]*GormField, 0)
 	modelColumnSet := NewStringSet(getGormModelColumns()...)
+	embeds := make([]*Embed, 0)
+	embedSet := make(map[*Embed]bool)
 	for _, column := range table.Columns {
+		// columns of embed
+		if embed := column.Embed; embed != nil {
+			if !embedSet[embed] {
+				embeds = append(embeds, embed)
+				embedSet[embed] = true
+			}
+			continue
+		}
+
 		field := NewGormField(column)
 		fields = append(fields, field)
 
@@ -75,9 +112,11 @@ func NewGormClass(
 		table:        table,
 		Name:         className,
 		EmbedModel:   modelColumnSet.Size() == 0,
+		Imports:      target.Imports,
 		Fields:       fields,
 		PKFields:     pkFields,
 		UniqueFields: uniqueFields,
+		Embeds:       embeds,
 	}
 }
 
@@ -167,12 +206,25 @@ func NewGormField(column *Column) *GormField {
 		fieldName = string(re.ReplaceAll([]byte(fieldName), []byte("ID")))
 	}
 
+	// override by target
+	target := column.Target(FormatGorm)
+	if target.Name != "" {
+		fieldName = target.Name
+		ok = false
+	}
+	if target.Type != "" {
+		fieldType = target.Type
+		importSet.Clear()
+	}
+	importSet.AddAll(target.Imports)
+
 	return &GormField{
 		Column:       column,
 		Name:         fieldName,
 		Type:         fieldType,
 		OverrideName: !ok,
 		Imports:      importSet.Slice(),
+		Tags:         target.Annotations,
 	}
 }
 
@@ -191,6 +243,23 @@ func (g *Gorm) Generate(
 	prefixMapper *PrefixMapper,
 ) error {
 	uniqueNameSuffix := output.Get(FlagUniqueNameSuffix)
+	audit, err := newAuditColumns(output.Get(FlagAudit))
+	if err != nil {
+		return err
+	}
+	version, err := getGormVersion(output)
+	if err != nil {
+		return err
+	}
+	// audit columns are tracked by tags of GORM v2
+	if audit.declared && version != GormVersion2 {
+		return fmt.Errorf("audit columns require GORM %s. gormVersion: %s", GormVersion2, version)
+	}
+	isV2 := version == GormVersion2
+	gormImport := "github.com/jinzhu/gorm"
+	if isV2 {
+		gormImport = "gorm.io/gorm"
+	}
 	gormModel := output.Get(FlagGormModel)
 	if gormModel == "" {
 		gormModel = "gorm.Model"
@@ -244,8 +313,9 @@ func (g *Gorm) Generate(
 
 		// embedded model
 		if class.EmbedModel {
-			importSet.Add("github.com/jinzhu/gorm")
+			importSet.Add(gormImport)
 		}
+		importSet.AddAll(class.Imports)
 
 		// unique
 		uniqueCstName := ""
@@ -277,6 +347,24 @@ func (g *Gorm) Generate(
 			// Column gormTags
 			gormTags := make([]string, 0)
 
+			// audit columns
+			if audit.declared {
+				switch audit.GetRole(column) {
+				case AuditCreated:
+					gormTags = append(gormTags, "autoCreateTime")
+				case AuditUpdated:
+					gormTags = append(gormTags, "autoUpdateTime")
+				case AuditDeleted:
+					if class.EmbedModel {
+						log.Printf("%s.%s is ignored. soft delete is provided by %s", table.Name, column.Name, gormModel)
+						break
+					}
+					field.Type = "gorm.DeletedAt"
+					field.Imports = []string{gormImport}
+					gormTags = append(gormTags, "index")
+				}
+			}
+
 			if field.OverrideName {
 				gormTags = append(gormTags, fmt.Sprintf("column:%s", column.Name))
 			}
@@ -289,19 +377,29 @@ func (g *Gorm) Generate(
 			}
 			// PK
 			if column.PrimaryKey {
-				gormTags = append(gormTags, "primary_key")
+				if isV2 {
+					gormTags = append(gormTags, "primaryKey")
+				} else {
+					gormTags = append(gormTags, "primary_key")
+				}
 			}
 			// Unique
 			if column.UniqueKey {
 				if uniqueCstName == "" {
 					gormTags = append(gormTags, "unique")
+				} else if isV2 {
+					gormTags = append(gormTags, fmt.Sprintf("uniqueIndex:%s", uniqueCstName))
 				} else {
 					gormTags = append(gormTags, fmt.Sprintf("unique_index:%s", uniqueCstName))
 				}
 			}
 			// auto_increment
 			if column.AutoIncremental {
-				gormTags = append(gormTags, "auto_increment")
+				if isV2 {
+					gormTags = append(gormTags, "autoIncrement")
+				} else {
+					gormTags = append(gormTags, "auto_increment")
+				}
 			}
 			// not null
 			if !column.Nullable && !column.AutoIncremental {
@@ -309,11 +407,15 @@ func (g *Gorm) Generate(
 			}
 
 			// GORM tag
-			if len(gormTags) == 0 {
+			tags := make([]string, 0)
+			if len(gormTags) > 0 {
+				tags = append(tags, fmt.Sprintf("gorm:\"%s\"", strings.Join(gormTags, ";")))
+			}
+			tags = append(tags, field.Tags...)
+			if len(tags) == 0 {
 				appendLine(indent + fmt.Sprintf("%s %s", field.Name, field.Type))
 			} else {
-				tag := fmt.Sprintf("`gorm:\"%s\"`", strings.Join(gormTags, ";"))
-				appendLine(indent + fmt.Sprintf("%s %s %s", field.Name, field.Type, tag))
+				appendLine(indent + fmt.Sprintf("%s %s `%s`", field.Name, field.Type, strings.Join(tags, " ")))
 			}
 
 			// import
@@ -321,6 +423,17 @@ func (g *Gorm) Generate(
 				importSet.Add(imp)
 			}
 		}
+
+		// embedded structs
+		embeddedPrefixTag := "embedded_prefix"
+		if isV2 {
+			embeddedPrefixTag = "embeddedPrefix"
+		}
+		for _, embed := range class.Embeds {
+			appendLine(indent + fmt.Sprintf("%s %s `gorm:\"embedded;%s:%s\"`",
+				strcase.ToCamel(embed.FieldName()), strcase.ToCamel(embed.Embeddable), embeddedPrefixTag, embed.Prefix))
+		}
+
 		classLines = append(classLines,
 			"}",
 			fmt.Sprintf("func (c *%s) TableName() string { return \"%s\" }", class.Name, table.Name))
@@ -344,6 +457,31 @@ func (g *Gorm) Generate(
 		}
 	}
 
+	// embeddable structs
+	embeddableNameSet := NewStringSet()
+	for _, class := range classes {
+		for _, embed := range class.Embeds {
+			embeddableNameSet.Add(embed.Embeddable)
+		}
+	}
+	for _, name := range embeddableNameSet.Slice() {
+		lines, imports := g.embeddableLines(schema.EmbeddableByName(name), indent)
+		if generateSingleFile {
+			contents = append(contents, lines...)
+			importSet.AddAll(imports)
+			continue
+		}
+
+		fileContents := []string{fmt.Sprintf("package %s", pkg)}
+		fileContents = append(fileContents, g.getHeaderLines(indent, imports)...)
+		fileContents = append(fileContents, lines...)
+		fileContents = append(fileContents, "")
+		outputFile := path.Join(outputDir, fmt.Sprintf("%s_embeddable.go", strcase.ToSnake(name)))
+		if err := WriteLinesToFile(outputFile, fileContents); err != nil {
+			return err
+		}
+	}
+
 	// Write to single file
 	if generateSingleFile {
 		finalOutput := []string{
@@ -362,6 +500,38 @@ func (g *Gorm) Generate(
 	return nil
 }
 
+// embeddableLines returns struct of embeddable and imports.
+func (g *Gorm) embeddableLines(embeddable *Embeddable, indent string) ([]string, []string) {
+	importSet := NewStringSet()
+	lines := []string{"", "", fmt.Sprintf("type %s struct {", strcase.ToCamel(embeddable.Name))}
+	for _, column := range embeddable.Columns {
+		field := NewGormField(column)
+		importSet.AddAll(field.Imports)
+
+		gormTags := make([]string, 0)
+		if field.OverrideName {
+			gormTags = append(gormTags, fmt.Sprintf("column:%s", column.Name))
+		}
+		if column.Type == ColTypeString && column.Size > 0 {
+			gormTags = append(gormTags, fmt.Sprintf("type:varchar(%d)", column.Size))
+		} else if (column.Type == ColTypeDouble || column.Type == ColTypeFloat || column.Type == ColTypeDecimal) &&
+			(column.Size > 0 && column.Scale > 0) {
+			gormTags = append(gormTags, fmt.Sprintf("type:%s(%d,%d)", column.Type, column.Size, column.Scale))
+		}
+		if !column.Nullable {
+			gormTags = append(gormTags, "not null")
+		}
+
+		if len(gormTags) == 0 {
+			lines = append(lines, indent+fmt.Sprintf("%s %s", field.Name, field.Type))
+		} else {
+			lines = append(lines, indent+fmt.Sprintf("%s %s `gorm:\"%s\"`", field.Name, field.Type, strings.Join(gormTags, ";")))
+		}
+	}
+	lines = append(lines, "}")
+	return lines, importSet.Slice()
+}
+
 func (g *Gorm) getHeaderLines(indent string, imports []string) []string {
 	lines := make([]string, 0)
 
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"strings"
+	"testing"
+)
+
+func TestGorm_Generate_Audit(t *testing.T) {
+	schema := &Schema{
+		Tables: []*Table{
+			{
+				Name: "user",
+				Columns: []*Column{
+					{Name: "id", Type: ColTypeLong, PrimaryKey: true, AutoIncremental: true},
+					{Name: "created_at", Type: ColTypeDateTime},
+					{Name: "modified_at", Type: ColTypeDateTime},
+					{Name: "removed_at", Type: ColTypeDateTime, Nullable: true},
+				},
+			},
+		},
+	}
+
+	files, err := generateOutput(t, schema, &Output{FilePath: "models.go", Format: FormatGorm, Options: map[string]string{
+		FlagAudit:       "created:created_at,updated:modified_at,deleted:removed_at",
+		FlagGormVersion: GormVersion2,
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual := files["models.go"]
+
+	expected := strings.Join([]string{
+		"package main",
+		"",
+		"import (",
+		"    \"gorm.io/gorm\"",
+		"    \"time\"",
+		")",
+		"",
+		"",
+		"type User struct {",
+		"    ID uint `gorm:\"primaryKey;autoIncrement\"`",
+		"    CreatedAt time.Time `gorm:\"autoCreateTime;not null\"`",
+		"    ModifiedAt time.Time `gorm:\"autoUpdateTime;not null\"`",
+		"    RemovedAt gorm.DeletedAt `gorm:\"index\"`",
+		"}",
+		"func (c *User) TableName() string { return \"user\" }",
+		"",
+	}, "\n")
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Errorf("TestGorm_Generate_Audit() mismatch (-expected +actual):\n%s", diff)
+	}
+}
+
+func TestGorm_Generate_Version(t *testing.T) {
+	schema := &Schema{
+		Tables: []*Table{
+			{
+				Name: "user",
+				Columns: []*Column{
+					{Name: "id", Type: ColTypeLong, PrimaryKey: true, AutoIncremental: true},
+					{Name: "name", Type: ColTypeString, Size: 20, UniqueKey: true},
+					{Name: "email", Type: ColTypeString, Size: 40, UniqueKey: true},
+					{Name: "created_at", Type: ColTypeDateTime},
+					{Name: "updated_at", Type: ColTypeDateTime},
+					{Name: "deleted_at", Type: ColTypeDateTime, Nullable: true},
+				},
+			},
+			{
+				Name: "group",
+				Columns: []*Column{
+					{Name: "code", Type: ColTypeString, Size: 10, PrimaryKey: true},
+					{Name: "removed_at", Type: ColTypeDateTime, Nullable: true},
+				},
+			},
+		},
+	}
+
+	// embedded model and soft delete share the import of GORM v2
+	files, err := generateOutput(t, schema, &Output{FilePath: "models.go", Format: FormatGorm, Options: map[string]string{
+		FlagAudit:            "deleted:removed_at",
+		FlagGormVersion:      GormVersion2,
+		FlagUniqueNameSuffix: "_uq",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual := files["models.go"]
+
+	expected := strings.Join([]string{
+		"package main",
+		"",
+		"import (",
+		"    \"gorm.io/gorm\"",
+		")",
+		"",
+		"",
+		"type User struct {",
+		"    gorm.Model",
+		"    Name string `gorm:\"type:varchar(20);uniqueIndex:user_uq;not null\"`",
+		"    Email string `gorm:\"type:varchar(40);uniqueIndex:user_uq;not null\"`",
+		"}",
+		"func (c *User) TableName() string { return \"user\" }",
+		"",
+		"",
+		"type Group struct {",
+		"    Code string `gorm:\"type:varchar(10);primaryKey;not null\"`",
+		"    RemovedAt gorm.DeletedAt `gorm:\"index\"`",
+		"}",
+		"func (c *Group) TableName() string { return \"group\" }",
+		"",
+	}, "\n")
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Errorf("TestGorm_Generate_Version() mismatch (-expected +actual):\n%s", diff)
+	}
+
+	// audit columns are not supported by GORM v1
+	_, err = generateOutput(t, schema, &Output{FilePath: "models.go", Format: FormatGorm, Options: map[string]string{
+		FlagAudit: "deleted:removed_at",
+	}})
+	if err == nil || !strings.Contains(err.Error(), "audit columns require GORM v2") {
+		t.Errorf("TestGorm_Generate_Version() unexpected error: %v", err)
+	}
+
+	_, err = generateOutput(t, schema, &Output{FilePath: "models.go", Format: FormatGorm, Options: map[string]string{
+		FlagGormVersion: "v3",
+	}})
+	if err == nil || err.Error() != "unsupported GORM version: v3" {
+		t.Errorf("TestGorm_Generate_Version() unexpected error: %v", err)
+	}
+}
+
+func TestGorm_Generate_Target(t *testing.T) {
+	schema := &Schema{
+		Tables: []*Table{
+			{
+				Name: "user",
+				Columns: []*Column{
+					{Name: "id", Type: ColTypeLong, PrimaryKey: true, AutoIncremental: true},
+					{
+						Name: "role",
+						Type: ColTypeString,
+						Size: 20,
+						Targets: map[string]*Target{
+							FormatGorm: {
+								Name:        "UserRole",
+								Type:        "types.Role",
+								Annotations: []string{`json:"role"`},
+								Imports:     []string{"example.com/types"},
+							},
+						},
+					},
+				},
+				Targets: map[string]*Target{
+					FormatGorm: {Name: "Member"},
+				},
+			},
+		},
+	}
+
+	files, err := generateOutput(t, schema, &Output{FilePath: "models.go", Format: FormatGorm, Options: map[string]string{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual := files["models.go"]
+
+	expected := strings.Join([]string{
+		"package main",
+		"",
+		"import (",
+		"    \"example.com/types\"",
+		")",
+		"",
+		"",
+		"type Member struct {",
+		"    ID uint `gorm:\"primary_key;auto_increment\"`",
+		"    UserRole types.Role `gorm:\"column:role;type:varchar(20);not null\" json:\"role\"`",
+		"}",
+		"func (c *Member) TableName() string { return \"user\" }",
+		"",
+	}, "\n")
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Errorf("TestGorm_Generate_Target() mismatch (-expected +actual):\n%s", diff)
+	}
+}
+
+func TestGorm_Generate_Embeds(t *testing.T) {
+	schema := newEmbedTestSchema()
+	schema.Normalize()
+
+	files, err := generateOutput(t, schema, &Output{FilePath: "models.go", Format: FormatGorm, Options: map[string]string{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual := files["models.go"]
+
+	expected := strings.Join([]string{
+		"package main",
+		"",
+		"import (",
+		"    \"gopkg.in/guregu/null.v3\"",
+		")",
+		"",
+		"",
+		"type User struct {",
+		"    ID uint `gorm:\"primary_key;not null\"`",
+		"    Home Address `gorm:\"embedded;embedded_prefix:home_\"`",
+		"    Work Address `gorm:\"embedded;embedded_prefix:work_\"`",
+		"}",
+		"func (c *User) TableName() string { return \"user\" }",
+		"",
+		"",
+		"type Address struct {",
+		"    City string `gorm:\"type:varchar(40);not null\"`",
+		"    ZipCode null.String `gorm:\"type:varchar(10)\"`",
+		"}",
+		"",
+	}, "\n")
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Errorf("TestGorm_Generate_Embeds() mismatch (-expected +actual):\n%s", diff)
+	}
+}
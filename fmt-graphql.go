@@ -11,17 +11,29 @@ import (
 	"strings"
 )
 
+const (
+	GraphqlFrameworkCoxautodev = "coxautodev"
+	GraphqlFrameworkSpring     = "spring"
+	GraphqlFrameworkDgs        = "dgs"
+
+	graphqlDefaultPageSize = 20
+)
+
 type GraphqlClass struct {
 	table    *Table
 	Name     string
 	Fields   []*GraphqlField
 	PKFields []*GraphqlField
+	// directives
+	Annotations []string
 }
 
 type GraphqlField struct {
 	Column *Column
 	Name   string
 	Type   string
+	// directives
+	Annotations []string
 }
 
 type Graphql struct {
@@ -46,6 +58,11 @@ func NewGraphqlClass(
 		}
 	}
 
+	target := table.Target(FormatGraphql)
+	if target.Name != "" {
+		className = target.Name
+	}
+
 	fields := make([]*GraphqlField, 0)
 	pkFields := make([]*GraphqlField, 0)
 	for _, column := range table.Columns {
@@ -57,14 +74,29 @@ func NewGraphqlClass(
 		}
 	}
 
-	if len(pkFields) == 1 {
+	if len(pkFields) == 1 && pkFields[0].Column.Target(FormatGraphql).Type == "" {
 		pkFields[0].Type = "ID!"
 	}
 
 	return &GraphqlClass{
-		table:  table,
-		Name:   className,
-		Fields: fields,
+		table:    table,
+		Name:     className,
+		Fields:   fields,
+		PKFields: pkFields,
+
+		Annotations: target.Annotations,
+	}
+}
+
+func getGraphqlFramework(output *Output) (string, error) {
+	framework := strings.ToLower(output.Get(FlagGraphqlFramework))
+	switch framework {
+	case "":
+		return GraphqlFrameworkCoxautodev, nil
+	case GraphqlFrameworkCoxautodev, GraphqlFrameworkSpring, GraphqlFrameworkDgs:
+		return framework, nil
+	default:
+		return "", fmt.Errorf("unsupported graphql framework: %s", framework)
 	}
 }
 
@@ -103,10 +135,22 @@ func NewGraphqlField(column *Column) *GraphqlField {
 		fieldType = fieldType + "!"
 	}
 
+	fieldName := strcase.ToLowerCamel(column.Name)
+
+	// override by target
+	target := column.Target(FormatGraphql)
+	if target.Name != "" {
+		fieldName = target.Name
+	}
+	if target.Type != "" {
+		fieldType = target.Type
+	}
+
 	return &GraphqlField{
-		Column: column,
-		Name:   strcase.ToLowerCamel(column.Name),
-		Type:   fieldType,
+		Column:      column,
+		Name:        fieldName,
+		Type:        fieldType,
+		Annotations: target.Annotations,
 	}
 }
 
@@ -116,6 +160,15 @@ func (g *Graphql) Generate(
 	tableFilterFn TableFilterFn,
 	prefixMapper *PrefixMapper,
 ) error {
+	framework, err := getGraphqlFramework(output)
+	if err != nil {
+		return err
+	}
+	// spring-graphql and DGS resolvers support pagination, by-id lookup and references.
+	useResolvers := framework != GraphqlFrameworkCoxautodev
+	// reference columns of @ManyToOne entity are replaced by fields of referenced types.
+	manyToOne := output.Get(FlagRelation) == JpaRelationManyToOne
+
 	// Create directory
 	if err := os.MkdirAll(output.FilePath, 0777); err != nil {
 		return err
@@ -136,6 +189,7 @@ func (g *Graphql) Generate(
 `)
 
 	classes := make([]*GraphqlClass, 0)
+	classByTable := make(map[string]*GraphqlClass)
 
 	client := pluralize.NewClient()
 
@@ -148,18 +202,64 @@ func (g *Graphql) Generate(
 
 		class := NewGraphqlClass(table, output, prefixMapper)
 		classes = append(classes, class)
+		classByTable[table.Name] = class
 
 		lowerClassName := strcase.ToLowerCamel(class.Name)
-		appendLine(1, fmt.Sprintf("%s: [%s]", client.Plural(lowerClassName), class.Name))
+		listName := client.Plural(lowerClassName)
+		if !useResolvers {
+			appendLine(1, fmt.Sprintf("%s: [%s]", listName, class.Name))
+			continue
+		}
+		appendLine(1, fmt.Sprintf("%s(page: Int, size: Int): [%s]", listName, class.Name))
+		if len(class.PKFields) == 1 {
+			byIdName := lowerClassName
+			if byIdName == listName {
+				byIdName = byIdName + "ById"
+			}
+			appendLine(1, fmt.Sprintf("%s(id: ID!): %s", byIdName, class.Name))
+		}
 	}
 	appendLine(0, "}")
 	appendLine(0, "")
 
 	for _, class := range classes {
-		appendLine(0, fmt.Sprintf("type %s {", class.Name))
+		appendLine(0, strings.Join(append(append([]string{"type", class.Name}, class.Annotations...), "{"), " "))
+
+		fieldNameSet := NewStringSet()
+		for _, field := range class.Fields {
+			fieldNameSet.Add(field.Name)
+		}
 
+		manyToOneFieldSet := NewStringSet()
 		for _, field := range class.Fields {
-			appendLine(1, fmt.Sprintf("%s: %s", field.Name, field.Type))
+			if ref := field.Column.Ref; manyToOne && ref != nil && !field.Column.PrimaryKey {
+				if refClass, ok := classByTable[ref.Table]; ok {
+					fieldNameSet.Remove(field.Name)
+					name := refFieldName(fieldNameSet, field.Column, refClass.Name)
+					fieldNameSet.Add(name)
+					manyToOneFieldSet.Add(field.Name)
+					appendLine(1, fmt.Sprintf("%s: %s", name, refClass.Name))
+					continue
+				}
+			}
+			appendLine(1, strings.Join(append([]string{field.Name + ":", field.Type}, field.Annotations...), " "))
+		}
+
+		// fields of referenced types
+		if useResolvers {
+			for _, field := range class.Fields {
+				ref := field.Column.Ref
+				if ref == nil || manyToOneFieldSet.Contains(field.Name) {
+					continue
+				}
+				refClass, ok := classByTable[ref.Table]
+				if !ok || len(refClass.PKFields) != 1 || refClass.PKFields[0].Column.Name != ref.Column {
+					continue
+				}
+				name := refFieldName(fieldNameSet, field.Column, refClass.Name)
+				fieldNameSet.Add(name)
+				appendLine(1, fmt.Sprintf("%s: %s", name, refClass.Name))
+			}
 		}
 		appendLine(0, "}")
 		appendLine(0, "")
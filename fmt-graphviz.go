@@ -0,0 +1,173 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"io/ioutil"
+	"strings"
+)
+
+type Graphviz struct {
+	TableFilterFn TableFilterFn
+}
+
+func (f *Graphviz) FromFile(filename string) error {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+
+	return f.FromString(data)
+}
+
+func (f *Graphviz) FromString(data []byte) error {
+	// TODO
+	return errors.New("not implemented")
+}
+
+func (f *Graphviz) ToSchema() (*Schema, error) {
+	// TODO
+	return nil, errors.New("not implemented")
+}
+
+func (f *Graphviz) ToFile(schema *Schema, filename string) error {
+	data, err := f.ToString(schema)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(filename, data, 0644)
+}
+
+func (f *Graphviz) ToString(schema *Schema) ([]byte, error) {
+	indent := "    "
+
+	tablesByGroup := make(map[string][]*Table)
+	tableNameSet := NewStringSet()
+	for _, table := range schema.Tables {
+		// filter table
+		if f.TableFilterFn != nil && !f.TableFilterFn(table) {
+			continue
+		}
+		tablesByGroup[table.Group] = append(tablesByGroup[table.Group], table)
+		tableNameSet.Add(table.Name)
+	}
+
+	result := []string{
+		"digraph erd {",
+		indent + `graph [rankdir=RL, splines=true, overlap=false, nodesep=0.6, ranksep=1.2, fontname="Helvetica"];`,
+		indent + `node [shape=plaintext, fontname="Helvetica", fontsize=10];`,
+		indent + `edge [dir=both, color="#555555"];`,
+	}
+
+	clusterIndex := 0
+	for _, group := range schema.Groups() {
+		tables := tablesByGroup[group]
+		if len(tables) == 0 {
+			continue
+		}
+
+		nodeIndent := indent
+		if group != "" {
+			result = append(result, "",
+				fmt.Sprintf("%ssubgraph cluster_%d {", indent, clusterIndex),
+				fmt.Sprintf("%s%slabel=%s;", indent, indent, f.quote(group)),
+				fmt.Sprintf(`%s%sstyle="rounded,dashed";`, indent, indent),
+				fmt.Sprintf(`%s%scolor="#999999";`, indent, indent),
+			)
+			nodeIndent = indent + indent
+			clusterIndex++
+		}
+
+		for _, table := range tables {
+			result = append(result, "", nodeIndent+f.getNodeDef(table))
+		}
+
+		if group != "" {
+			result = append(result, indent+"}")
+		}
+	}
+
+	// edges
+	result = append(result, "")
+	for _, table := range schema.Tables {
+		if !tableNameSet.Contains(table.Name) {
+			continue
+		}
+		for _, column := range table.Columns {
+			if ref := column.Ref; ref != nil && tableNameSet.Contains(ref.Table) {
+				result = append(result, indent+f.getEdgeDef(table, column))
+			}
+		}
+	}
+
+	result = append(result, "}", "")
+
+	return []byte(strings.Join(result, "\n")), nil
+}
+
+// getNodeDef returns table node with HTML-like label.
+func (f *Graphviz) getNodeDef(table *Table) string {
+	lines := []string{
+		`<TABLE BORDER="0" CELLBORDER="1" CELLSPACING="0" CELLPADDING="4">`,
+		fmt.Sprintf(`<TR><TD BGCOLOR="#DDE4F0" COLSPAN="3"><B>%s</B></TD></TR>`, html.EscapeString(table.Name)),
+	}
+	for _, column := range table.Columns {
+		name := html.EscapeString(column.Name)
+		if column.PrimaryKey {
+			name = "<U>" + name + "</U>"
+		}
+		if !column.Nullable {
+			name = "<B>" + name + "</B>"
+		}
+		lines = append(lines, fmt.Sprintf(
+			`<TR><TD PORT="%s" ALIGN="LEFT">%s</TD><TD ALIGN="LEFT">%s</TD><TD ALIGN="LEFT">%s</TD></TR>`,
+			html.EscapeString(column.Name),
+			name,
+			html.EscapeString(f.getColumnType(column)),
+			strings.Join(f.getKeys(column), ",")))
+	}
+	lines = append(lines, "</TABLE>")
+
+	return fmt.Sprintf("%s [label=<%s>];", f.quote(table.Name), strings.Join(lines, ""))
+}
+
+func (f *Graphviz) getColumnType(col *Column) string {
+	if col.Size > 0 {
+		if col.Scale > 0 {
+			return fmt.Sprintf("%s(%d,%d)", col.Type, col.Size, col.Scale)
+		}
+		return fmt.Sprintf("%s(%d)", col.Type, col.Size)
+	}
+	return col.Type
+}
+
+func (f *Graphviz) getKeys(col *Column) []string {
+	keys := make([]string, 0)
+	if col.PrimaryKey {
+		keys = append(keys, "PK")
+	}
+	if col.Ref != nil {
+		keys = append(keys, "FK")
+	}
+	if col.UniqueKey {
+		keys = append(keys, "UK")
+	}
+	return keys
+}
+
+// getEdgeDef returns edge from foreign key column to referenced column.
+func (f *Graphviz) getEdgeDef(table *Table, col *Column) string {
+	arrowHead := TernaryString(col.Nullable, "teeodot", "teetee")
+	arrowTail := TernaryString(col.UniqueKey, "teeodot", "crowodot")
+
+	return fmt.Sprintf("%s:%s -> %s:%s [arrowhead=%s, arrowtail=%s];",
+		f.quote(table.Name), f.quote(col.Name),
+		f.quote(col.Ref.Table), f.quote(col.Ref.Column),
+		arrowHead, arrowTail)
+}
+
+func (f *Graphviz) quote(s string) string {
+	return Quote(strings.ReplaceAll(s, `"`, `\"`), `"`)
+}
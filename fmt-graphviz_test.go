@@ -0,0 +1,105 @@
+package main
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"strings"
+	"testing"
+)
+
+func TestGraphviz_ToString(t *testing.T) {
+	schema := &Schema{
+		Tables: []*Table{
+			{
+				Name:  "group",
+				Group: "common",
+				Columns: []*Column{
+					{Name: "id", Type: ColTypeLong, PrimaryKey: true},
+					{Name: "name", Type: ColTypeString, Size: 40, Nullable: true, UniqueKey: true},
+				},
+			},
+			{
+				Name: "user",
+				Columns: []*Column{
+					{Name: "id", Type: ColTypeLong, PrimaryKey: true},
+					{Name: "group_id", Type: ColTypeLong, Nullable: true, Ref: &Reference{Table: "group", Column: "id"}},
+					{Name: "point", Type: ColTypeDecimal, Size: 10, Scale: 2},
+				},
+			},
+		},
+	}
+
+	graphviz := Graphviz{}
+	data, err := graphviz.ToString(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := strings.Join([]string{
+		"digraph erd {",
+		`    graph [rankdir=RL, splines=true, overlap=false, nodesep=0.6, ranksep=1.2, fontname="Helvetica"];`,
+		`    node [shape=plaintext, fontname="Helvetica", fontsize=10];`,
+		`    edge [dir=both, color="#555555"];`,
+		"",
+		`    "user" [label=<<TABLE BORDER="0" CELLBORDER="1" CELLSPACING="0" CELLPADDING="4">` +
+			`<TR><TD BGCOLOR="#DDE4F0" COLSPAN="3"><B>user</B></TD></TR>` +
+			`<TR><TD PORT="id" ALIGN="LEFT"><B><U>id</U></B></TD><TD ALIGN="LEFT">long</TD><TD ALIGN="LEFT">PK</TD></TR>` +
+			`<TR><TD PORT="group_id" ALIGN="LEFT">group_id</TD><TD ALIGN="LEFT">long</TD><TD ALIGN="LEFT">FK</TD></TR>` +
+			`<TR><TD PORT="point" ALIGN="LEFT"><B>point</B></TD><TD ALIGN="LEFT">decimal(10,2)</TD><TD ALIGN="LEFT"></TD></TR>` +
+			`</TABLE>>];`,
+		"",
+		"    subgraph cluster_0 {",
+		`        label="common";`,
+		`        style="rounded,dashed";`,
+		`        color="#999999";`,
+		"",
+		`        "group" [label=<<TABLE BORDER="0" CELLBORDER="1" CELLSPACING="0" CELLPADDING="4">` +
+			`<TR><TD BGCOLOR="#DDE4F0" COLSPAN="3"><B>group</B></TD></TR>` +
+			`<TR><TD PORT="id" ALIGN="LEFT"><B><U>id</U></B></TD><TD ALIGN="LEFT">long</TD><TD ALIGN="LEFT">PK</TD></TR>` +
+			`<TR><TD PORT="name" ALIGN="LEFT">name</TD><TD ALIGN="LEFT">string(40)</TD><TD ALIGN="LEFT">UK</TD></TR>` +
+			`</TABLE>>];`,
+		"    }",
+		"",
+		`    "user":"group_id" -> "group":"id" [arrowhead=teeodot, arrowtail=crowodot];`,
+		"}",
+		"",
+	}, "\n")
+	if diff := cmp.Diff(expected, string(data)); diff != "" {
+		t.Errorf("TestGraphviz_ToString() mismatch (-expected +actual):\n%s", diff)
+	}
+}
+
+func TestGraphviz_ToString_Groups(t *testing.T) {
+	schema := &Schema{
+		Tables: []*Table{
+			{
+				Name:    "group",
+				Group:   "common",
+				Columns: []*Column{{Name: "id", Type: ColTypeLong, PrimaryKey: true}},
+			},
+			{
+				Name:  "user",
+				Group: "user",
+				Columns: []*Column{
+					{Name: "group_id", Type: ColTypeLong, Ref: &Reference{Table: "group", Column: "id"}},
+				},
+			},
+		},
+	}
+
+	graphviz := Graphviz{TableFilterFn: getTableFilterFn("user")}
+	data, err := graphviz.ToString(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output := string(data)
+	if strings.Contains(output, `"group" [label=`) {
+		t.Errorf("TestGraphviz_ToString_Groups() filtered table is written:\n%s", output)
+	}
+	if strings.Contains(output, "->") {
+		t.Errorf("TestGraphviz_ToString_Groups() edge to filtered table is written:\n%s", output)
+	}
+	if !strings.Contains(output, "subgraph cluster_0 {\n        label=\"user\";") {
+		t.Errorf("TestGraphviz_ToString_Groups() cluster not found:\n%s", output)
+	}
+}
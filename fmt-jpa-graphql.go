@@ -0,0 +1,185 @@
+package main
+
+import (
+	"fmt"
+	"github.com/gertd/go-pluralize"
+	"github.com/iancoleman/strcase"
+	"path"
+	"strings"
+)
+
+// kotlinGraphqlRef is a graphql field resolved from a reference column.
+type kotlinGraphqlRef struct {
+	Name     string
+	Field    *KotlinField
+	RefClass *KotlinClass
+}
+
+// graphqlRefs returns reference fields to be resolved by repository of referenced class.
+// @ManyToOne fields are not included since they're resolved by JPA.
+func (k *JPAKotlin) graphqlRefs(class *KotlinClass, classByTable map[string]*KotlinClass) []*kotlinGraphqlRef {
+	fieldNameSet := NewStringSet()
+	for _, field := range class.Fields {
+		fieldNameSet.Add(field.Name)
+	}
+
+	result := make([]*kotlinGraphqlRef, 0)
+	for _, field := range class.Fields {
+		ref := field.Column.Ref
+		if ref == nil || field.RefClass != nil {
+			continue
+		}
+		refClass, ok := classByTable[ref.Table]
+		if !ok || len(refClass.PKFields) != 1 || refClass.PKFields[0].Column.Name != ref.Column {
+			continue
+		}
+		name := refFieldName(fieldNameSet, field.Column, refClass.Name)
+		fieldNameSet.Add(name)
+		result = append(result, &kotlinGraphqlRef{Name: name, Field: field, RefClass: refClass})
+	}
+	return result
+}
+
+// generateGraphqlResolvers writes spring-graphql controller or DGS data fetcher per entity class.
+func (k *JPAKotlin) generateGraphqlResolvers(
+	graphqlDir string,
+	graphqlPackage string,
+	outputPackage string,
+	reposPackage string,
+	classes []*KotlinClass,
+	framework string,
+) error {
+	isDgs := framework == GraphqlFrameworkDgs
+	classByTable := make(map[string]*KotlinClass)
+	for _, class := range classes {
+		classByTable[class.table.Name] = class
+	}
+	reposName := func(class *KotlinClass) string {
+		return strcase.ToLowerCamel(class.Name) + "Repos"
+	}
+
+	client := pluralize.NewClient()
+	for _, class := range classes {
+		lowerClassName := strcase.ToLowerCamel(class.Name)
+		refs := k.graphqlRefs(class, classByTable)
+
+		importSet := NewStringSet()
+		if isDgs {
+			importSet.AddAll([]string{
+				"com.netflix.graphql.dgs.DgsComponent",
+				"com.netflix.graphql.dgs.DgsQuery",
+				"com.netflix.graphql.dgs.InputArgument",
+			})
+		} else {
+			importSet.AddAll([]string{
+				"org.springframework.graphql.data.method.annotation.Argument",
+				"org.springframework.graphql.data.method.annotation.QueryMapping",
+				"org.springframework.stereotype.Controller",
+			})
+		}
+		importSet.Add("org.springframework.data.domain.PageRequest")
+
+		// repositories of the class and referenced classes
+		ctorArgs := []string{fmt.Sprintf("        private val %s: %sRepository", reposName(class), class.Name)}
+		reposClassSet := NewStringSet(class.Name)
+		for _, ref := range refs {
+			if !reposClassSet.Contains(ref.RefClass.Name) {
+				reposClassSet.Add(ref.RefClass.Name)
+				ctorArgs = append(ctorArgs, fmt.Sprintf("        private val %s: %sRepository", reposName(ref.RefClass), ref.RefClass.Name))
+			}
+		}
+
+		methods := make([]string, 0)
+		queryAnno := "    @QueryMapping"
+		argAnno := "@Argument"
+		if isDgs {
+			queryAnno = "    @DgsQuery"
+			argAnno = "@InputArgument"
+		}
+
+		// list with pagination
+		listName := client.Plural(lowerClassName)
+		methods = append(methods,
+			"",
+			queryAnno,
+			fmt.Sprintf("    fun %s(%s page: Int?, %s size: Int?): List<%s> {", listName, argAnno, argAnno, class.Name),
+			fmt.Sprintf("        return %s.findAll(PageRequest.of(page ?: 0, size ?: %d)).content", reposName(class), graphqlDefaultPageSize),
+			"    }",
+		)
+
+		// by id
+		if len(class.PKFields) == 1 {
+			byIdName := lowerClassName
+			if byIdName == listName {
+				byIdName = byIdName + "ById"
+			}
+			importSet.Add("org.springframework.data.repository.findByIdOrNull")
+			importSet.AddAll(class.PKFields[0].Imports)
+			methods = append(methods,
+				"",
+				queryAnno,
+				fmt.Sprintf("    fun %s(%s id: %s): %s? {", byIdName, argAnno, strings.TrimSuffix(class.PKFields[0].Type, "?"), class.Name),
+				fmt.Sprintf("        return %s.findByIdOrNull(id)", reposName(class)),
+				"    }",
+			)
+		}
+
+		// references
+		for _, ref := range refs {
+			importSet.Add("org.springframework.data.repository.findByIdOrNull")
+			methods = append(methods, "")
+			if isDgs {
+				importSet.Add("com.netflix.graphql.dgs.DgsData")
+				importSet.Add("com.netflix.graphql.dgs.DgsDataFetchingEnvironment")
+				methods = append(methods,
+					fmt.Sprintf("    @DgsData(parentType = \"%s\", field = \"%s\")", class.Name, ref.Name),
+					fmt.Sprintf("    fun %s(dfe: DgsDataFetchingEnvironment): %s? {", ref.Name, ref.RefClass.Name),
+					fmt.Sprintf("        val %s = dfe.getSource<%s>()", lowerClassName, class.Name),
+				)
+			} else {
+				importSet.Add("org.springframework.graphql.data.method.annotation.SchemaMapping")
+				methods = append(methods,
+					fmt.Sprintf("    @SchemaMapping(typeName = \"%s\", field = \"%s\")", class.Name, ref.Name),
+					fmt.Sprintf("    fun %s(%s: %s): %s? {", ref.Name, lowerClassName, class.Name, ref.RefClass.Name),
+				)
+			}
+			refId := lowerClassName + "." + ref.Field.Name
+			if strings.HasSuffix(ref.Field.Type, "?") {
+				methods = append(methods, fmt.Sprintf("        return %s?.let { %s.findByIdOrNull(it) }", refId, reposName(ref.RefClass)))
+			} else {
+				methods = append(methods, fmt.Sprintf("        return %s.findByIdOrNull(%s)", reposName(ref.RefClass), refId))
+			}
+			methods = append(methods, "    }")
+		}
+
+		resolverClassName := class.Name + "Controller"
+		classAnno := "@Controller"
+		if isDgs {
+			resolverClassName = class.Name + "DataFetcher"
+			classAnno = "@DgsComponent"
+		}
+
+		lines := []string{"package " + graphqlPackage, ""}
+		if outputPackage != "" {
+			lines = append(lines, "import "+outputPackage+".*")
+		}
+		lines = append(lines, "import "+reposPackage+".*")
+		for _, imp := range importSet.Slice() {
+			lines = append(lines, "import "+imp)
+		}
+		lines = append(lines,
+			"",
+			classAnno,
+			fmt.Sprintf("class %s(", resolverClassName),
+			strings.Join(ctorArgs, ",\n"),
+			") {",
+		)
+		lines = append(lines, methods[1:]...)
+		lines = append(lines, "}", "")
+
+		if err := k.writeLines(path.Join(graphqlDir, resolverClassName+".kt"), lines); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"strings"
+	"testing"
+)
+
+func newGraphqlResolverTestSchema() *Schema {
+	return &Schema{
+		Tables: []*Table{
+			{
+				Name: "group",
+				Columns: []*Column{
+					{Name: "id", Type: ColTypeLong, PrimaryKey: true},
+				},
+			},
+			{
+				Name: "user",
+				Columns: []*Column{
+					{Name: "id", Type: ColTypeLong, PrimaryKey: true},
+					{Name: "group", Type: ColTypeString},
+					{Name: "group_id", Type: ColTypeLong, Nullable: true, Ref: &Reference{Table: "group", Column: "id"}},
+					{Name: "owner_id", Type: ColTypeLong, Ref: &Reference{Table: "user", Column: "id"}},
+					{Name: "news_title", Type: ColTypeString, Ref: &Reference{Table: "news", Column: "title"}},
+				},
+			},
+			{
+				Name: "news",
+				Columns: []*Column{
+					{Name: "id", Type: "uuid", PrimaryKey: true},
+					{Name: "title", Type: ColTypeString},
+				},
+			},
+			{
+				Name: "user_role",
+				Columns: []*Column{
+					{Name: "user_id", Type: ColTypeLong, PrimaryKey: true},
+					{Name: "role", Type: ColTypeString, PrimaryKey: true},
+				},
+			},
+		},
+	}
+}
+
+func TestJPAKotlin_graphqlRefs(t *testing.T) {
+	schema := newGraphqlResolverTestSchema()
+	output := &Output{Options: map[string]string{}}
+	classByTable := make(map[string]*KotlinClass)
+	for _, table := range schema.Tables {
+		classByTable[table.Name] = NewKotlinClass(table, output, newAnnotationMapper(""), newPrefixMapper(""))
+	}
+
+	k := &JPAKotlin{}
+	names := make([]string, 0)
+	for _, ref := range k.graphqlRefs(classByTable["user"], classByTable) {
+		names = append(names, ref.Name+":"+ref.RefClass.Name)
+	}
+
+	// reference to non primary key column is skipped
+	if diff := cmp.Diff([]string{"groupGroup:Group", "user:User"}, names); diff != "" {
+		t.Errorf("TestJPAKotlin_graphqlRefs() mismatch (-expected +actual):\n%s", diff)
+	}
+}
+
+func TestJPAKotlin_Generate_SpringGraphql(t *testing.T) {
+	files, err := generateOutput(t, newGraphqlResolverTestSchema(), &Output{Format: FormatJpaKotlinData, Options: map[string]string{
+		FlagPackage:          "com.example.entity",
+		FlagReposPackage:     "com.example.repos",
+		FlagGraphqlPackage:   "com.example.graphql",
+		FlagGraphqlFramework: GraphqlFrameworkSpring,
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := strings.Join([]string{
+		"package com.example.graphql",
+		"",
+		"import com.example.entity.*",
+		"import com.example.repos.*",
+		"import org.springframework.data.domain.PageRequest",
+		"import org.springframework.data.repository.findByIdOrNull",
+		"import org.springframework.graphql.data.method.annotation.Argument",
+		"import org.springframework.graphql.data.method.annotation.QueryMapping",
+		"import org.springframework.graphql.data.method.annotation.SchemaMapping",
+		"import org.springframework.stereotype.Controller",
+		"",
+		"@Controller",
+		"class UserController(",
+		"        private val userRepos: UserRepository,",
+		"        private val groupRepos: GroupRepository",
+		") {",
+		"    @QueryMapping",
+		"    fun users(@Argument page: Int?, @Argument size: Int?): List<User> {",
+		"        return userRepos.findAll(PageRequest.of(page ?: 0, size ?: 20)).content",
+		"    }",
+		"",
+		"    @QueryMapping",
+		"    fun user(@Argument id: Long): User? {",
+		"        return userRepos.findByIdOrNull(id)",
+		"    }",
+		"",
+		"    @SchemaMapping(typeName = \"User\", field = \"groupGroup\")",
+		"    fun groupGroup(user: User): Group? {",
+		"        return user.groupId?.let { groupRepos.findByIdOrNull(it) }",
+		"    }",
+		"",
+		"    @SchemaMapping(typeName = \"User\", field = \"user\")",
+		"    fun user(user: User): User? {",
+		"        return userRepos.findByIdOrNull(user.ownerId)",
+		"    }",
+		"}",
+		"",
+	}, "\n")
+	if diff := cmp.Diff(expected, files["com/example/graphql/UserController.kt"]); diff != "" {
+		t.Errorf("TestJPAKotlin_Generate_SpringGraphql() mismatch (-expected +actual):\n%s", diff)
+	}
+
+	// singular and plural names are the same
+	assertContains(t, "NewsController.kt", files["com/example/graphql/NewsController.kt"],
+		"    @QueryMapping",
+		"    fun newsById(@Argument id: UUID): News? {",
+	)
+	assertContains(t, "NewsController.kt", files["com/example/graphql/NewsController.kt"],
+		"import com.example.repos.*",
+		"import java.util.UUID",
+	)
+
+	// by-id lookup is not generated for composite primary key
+	userRole := files["com/example/graphql/UserRoleController.kt"]
+	if strings.Contains(userRole, "findByIdOrNull") {
+		t.Errorf("TestJPAKotlin_Generate_SpringGraphql() unexpected by-id lookup:\n%s", userRole)
+	}
+}
+
+func TestJPAKotlin_Generate_DgsGraphql(t *testing.T) {
+	schema := newGraphqlResolverTestSchema()
+	output := &Output{Options: map[string]string{}}
+	classes := make([]*KotlinClass, 0)
+	for _, table := range schema.Tables {
+		classes = append(classes, NewKotlinClass(table, output, newAnnotationMapper(""), newPrefixMapper("")))
+	}
+
+	// entities of default package
+	files, err := generateFiles(t, output.Options, func(output *Output) error {
+		k := &JPAKotlin{}
+		return k.generateGraphqlResolvers(output.FilePath, "com.example.graphql", "", "com.example.repos", classes, GraphqlFrameworkDgs)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fetcher := files["UserDataFetcher.kt"]
+	assertContains(t, "UserDataFetcher.kt", fetcher,
+		"package com.example.graphql",
+		"",
+		"import com.example.repos.*",
+		"import com.netflix.graphql.dgs.DgsComponent",
+	)
+	assertContains(t, "UserDataFetcher.kt", fetcher,
+		"@DgsComponent",
+		"class UserDataFetcher(",
+	)
+	assertContains(t, "UserDataFetcher.kt", fetcher,
+		"    @DgsQuery",
+		"    fun users(@InputArgument page: Int?, @InputArgument size: Int?): List<User> {",
+	)
+	assertContains(t, "UserDataFetcher.kt", fetcher,
+		"    @DgsData(parentType = \"User\", field = \"groupGroup\")",
+		"    fun groupGroup(dfe: DgsDataFetchingEnvironment): Group? {",
+		"        val user = dfe.getSource<User>()",
+		"        return user.groupId?.let { groupRepos.findByIdOrNull(it) }",
+		"    }",
+	)
+}
+
+func TestJPAKotlin_Generate_GraphqlWithoutRepos(t *testing.T) {
+	for _, framework := range []string{GraphqlFrameworkSpring, GraphqlFrameworkDgs} {
+		_, err := generateOutput(t, newGraphqlResolverTestSchema(), &Output{Format: FormatJpaKotlinData, Options: map[string]string{
+			FlagPackage:          "com.example.entity",
+			FlagGraphqlPackage:   "com.example.graphql",
+			FlagGraphqlFramework: framework,
+		}})
+		if err == nil || !strings.Contains(err.Error(), "reposPackage is required") {
+			t.Errorf("TestJPAKotlin_Generate_GraphqlWithoutRepos(%s) unexpected error: %v", framework, err)
+		}
+	}
+}
+
+func TestGraphql_Generate_ManyToOne(t *testing.T) {
+	schema := newGraphqlResolverTestSchema()
+	schema.Name = "test"
+	schema.Version = "1.0.0"
+
+	files, err := generateOutput(t, schema, &Output{Format: FormatGraphql, Options: map[string]string{
+		FlagGraphqlFramework: GraphqlFrameworkSpring,
+		FlagRelation:         JpaRelationManyToOne,
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// reference columns are replaced by fields of referenced types
+	assertContains(t, "test-1.0.0.graphqls", files["test-1.0.0.graphqls"],
+		"type User {",
+		"  id: ID!",
+		"  group: String!",
+		"  groupGroup: Group",
+		"  user: User",
+		"  news: News",
+		"}",
+	)
+}
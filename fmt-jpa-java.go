@@ -0,0 +1,479 @@
+package main
+
+import (
+	"fmt"
+	"github.com/iancoleman/strcase"
+	"log"
+	"path"
+	"strings"
+)
+
+type JavaClass struct {
+	table        *Table
+	Name         string
+	Annotations  []string
+	Fields       []*JavaField
+	PKFields     []*JavaField
+	UniqueFields []*JavaField
+}
+
+type JavaField struct {
+	Column       *Column
+	Name         string
+	OverrideName bool
+	Type         string
+	Imports      []string
+}
+
+type JPAJava struct {
+}
+
+func NewJavaClass(
+	table *Table,
+	output *Output,
+	annoMapper *AnnotationMapper,
+	prefixMapper *PrefixMapper,
+) *JavaClass {
+
+	fields := make([]*JavaField, 0)
+	pkFields := make([]*JavaField, 0)
+	uniqueFields := make([]*JavaField, 0)
+	for _, column := range table.Columns {
+		field := NewJavaField(column)
+		fields = append(fields, field)
+
+		if column.PrimaryKey {
+			pkFields = append(pkFields, field)
+		}
+		if column.UniqueKey {
+			uniqueFields = append(uniqueFields, field)
+		}
+	}
+
+	return &JavaClass{
+		table:        table,
+		Name:         jpaClassName(table, output, prefixMapper),
+		Annotations:  annoMapper.GetAnnotations(table.Group),
+		Fields:       fields,
+		PKFields:     pkFields,
+		UniqueFields: uniqueFields,
+	}
+}
+
+func NewJavaField(column *Column) *JavaField {
+	var fieldType string
+	importSet := NewStringSet()
+
+	columnType := strings.ToLower(column.Type)
+	switch columnType {
+	case ColTypeString, ColTypeText, "json":
+		fieldType = "String"
+	case ColTypeBoolean:
+		fieldType = "Boolean"
+	case ColTypeLong:
+		fieldType = "Long"
+	case ColTypeInt:
+		fieldType = "Integer"
+	case ColTypeDecimal:
+		fieldType = "BigDecimal"
+		importSet.Add("java.math.BigDecimal")
+	case ColTypeFloat:
+		fieldType = "Float"
+	case ColTypeDouble:
+		fieldType = "Double"
+	case ColTypeDateTime:
+		fieldType = "Timestamp"
+		importSet.Add("java.sql.Timestamp")
+	case ColTypeDate:
+		fieldType = "LocalDate"
+		importSet.Add("java.time.LocalDate")
+	case ColTypeTime:
+		fieldType = "LocalTime"
+		importSet.Add("java.time.LocalTime")
+	case ColTypeBlob:
+		fieldType = "Blob"
+		importSet.Add("java.sql.Blob")
+	case "uuid":
+		fieldType = "UUID"
+		importSet.Add("java.util.UUID")
+	case "bit":
+		if column.Size == 1 {
+			fieldType = "Boolean"
+		} else {
+			fieldType = "byte[]"
+		}
+	default:
+		fieldType = "Object"
+	}
+
+	fieldName, ok := ToLowerCamel(column.Name)
+
+	return &JavaField{
+		Column:       column,
+		Name:         fieldName,
+		OverrideName: !ok,
+		Type:         fieldType,
+		Imports:      importSet.Slice(),
+	}
+}
+
+func (j *JPAJava) Generate(
+	schema *Schema,
+	output *Output,
+	tableFilterFn TableFilterFn,
+	annoMapper *AnnotationMapper,
+	prefixMapper *PrefixMapper,
+) error {
+	outputPackage := output.Get(FlagPackage)
+	reposPackage := output.Get(FlagReposPackage)
+	uniqueNameSuffix := output.Get(FlagUniqueNameSuffix)
+	useLombok := output.GetBool(FlagLombok)
+	audit, err := newAuditColumns(output.Get(FlagAudit))
+	if err != nil {
+		return err
+	}
+
+	persistence := output.Get(FlagPersistence)
+	if persistence == "" {
+		persistence = JpaPersistenceJavax
+	}
+	if persistence != JpaPersistenceJavax && persistence != JpaPersistenceJakarta {
+		return fmt.Errorf("unsupported persistence: %s", persistence)
+	}
+	generationType := "AUTO"
+	if dbms := strings.ToLower(output.Get(FlagDbms)); dbms == DbmsMysql || dbms == "mariadb" {
+		generationType = "IDENTITY"
+	}
+
+	// directories are created in the same way as kotlin
+	k := &JPAKotlin{}
+	entityDir, err := k.mkdir(output.FilePath, outputPackage)
+	if err != nil {
+		return err
+	}
+	if entityDir == "" {
+		// default package
+		entityDir = output.FilePath
+	}
+	reposDir, err := k.mkdir(output.FilePath, reposPackage)
+	if err != nil {
+		return err
+	}
+
+	indent := strings.Repeat(" ", 4)
+
+	for _, table := range schema.Tables {
+		// filter table
+		if tableFilterFn != nil && !tableFilterFn(table) {
+			continue
+		}
+		class := NewJavaClass(table, output, annoMapper, prefixMapper)
+
+		idClassName := ""
+		pkFieldCount := len(class.PKFields)
+		if pkFieldCount > 1 {
+			idClassName = class.Name + ".PK"
+		} else if pkFieldCount == 1 {
+			idClassName = class.PKFields[0].Type
+		}
+
+		lines := make([]string, 0)
+		appendLine := func(line string) {
+			lines = append(lines, line)
+		}
+		importSet := NewStringSet(persistence + ".persistence.*")
+		javaImportSet := NewStringSet()
+
+		// unique
+		uniqueColumnNames := make([]string, 0)
+		for _, field := range class.UniqueFields {
+			uniqueColumnNames = append(uniqueColumnNames, Quote(field.Column.Name, "\""))
+		}
+
+		// class
+		for _, anno := range class.Annotations {
+			if anno != "" {
+				appendLine(anno)
+			}
+		}
+		appendLine("@Entity")
+		if len(uniqueColumnNames) == 0 {
+			appendLine(fmt.Sprintf("@Table(name = \"%s\")", table.Name))
+		} else {
+			appendLine(fmt.Sprintf("@Table(name = \"%s\", uniqueConstraints = {\n    @UniqueConstraint(name = \"%s\", columnNames = {%s})\n})",
+				table.Name, table.Name+uniqueNameSuffix, strings.Join(uniqueColumnNames, ", ")))
+		}
+		if pkFieldCount > 1 {
+			appendLine(fmt.Sprintf("@IdClass(%s.class)", idClassName))
+		}
+		auditAnnotations, auditImports := audit.jpaClassAnnotations(table, ".class", persistence)
+		for _, anno := range auditAnnotations {
+			appendLine(anno)
+		}
+		importSet.AddAll(auditImports)
+		if useLombok {
+			appendLine("@Getter")
+			appendLine("@Setter")
+			importSet.Add("lombok.Getter")
+			importSet.Add("lombok.Setter")
+		}
+		appendLine(fmt.Sprintf("public class %s {", class.Name))
+
+		// fields
+		for _, field := range class.Fields {
+			column := field.Column
+			if column.PrimaryKey {
+				appendLine(indent + "@Id")
+			}
+			if column.AutoIncremental {
+				appendLine(indent + fmt.Sprintf("@GeneratedValue(strategy = GenerationType.%s)", generationType))
+			}
+			if column.Type == ColTypeText {
+				appendLine(indent + "@Lob")
+			}
+
+			// @Column attributes
+			attributes := make([]string, 0)
+			if field.OverrideName {
+				attributes = append(attributes, fmt.Sprintf("name = \"%s\"", column.Name))
+			}
+			if !column.Nullable {
+				attributes = append(attributes, "nullable = false")
+			}
+			if column.Type == ColTypeString && column.Size > 0 {
+				attributes = append(attributes, fmt.Sprintf("length = %d", column.Size))
+			}
+			if column.Type == ColTypeDouble || column.Type == ColTypeFloat || column.Type == ColTypeDecimal {
+				if column.Size > 0 {
+					attributes = append(attributes, fmt.Sprintf("precision = %d", column.Size))
+				}
+				if column.Scale > 0 {
+					attributes = append(attributes, fmt.Sprintf("scale = %d", column.Scale))
+				}
+			}
+			// audit
+			auditAnnotations, notUpdatable, auditImports := audit.jpaFieldAnnotations(column)
+			for _, anno := range auditAnnotations {
+				appendLine(indent + anno)
+			}
+			if notUpdatable {
+				attributes = append(attributes, "updatable = false")
+			}
+			importSet.AddAll(auditImports)
+			if len(attributes) > 0 {
+				appendLine(indent + fmt.Sprintf("@Column(%s)", strings.Join(attributes, ", ")))
+			}
+			appendLine(indent + fmt.Sprintf("private %s %s;", field.Type, field.Name))
+			appendLine("")
+
+			// import
+			for _, imp := range field.Imports {
+				javaImportSet.Add(imp)
+			}
+		}
+
+		// getters, setters
+		if !useLombok {
+			lines = append(lines, j.accessors(class.Fields, indent)...)
+		}
+
+		// Composite Key
+		if pkFieldCount > 1 {
+			javaImportSet.Add("java.io.Serializable")
+			javaImportSet.Add("java.util.Objects")
+			lines = append(lines, j.idClass(class, indent, useLombok)...)
+		}
+
+		if lines[len(lines)-1] == "" {
+			lines = lines[:len(lines)-1]
+		}
+		appendLine("}")
+		appendLine("")
+
+		// contents
+		contents := make([]string, 0)
+		if outputPackage != "" {
+			contents = append(contents, fmt.Sprintf("package %s;", outputPackage), "")
+		}
+		for _, imp := range importSet.Slice() {
+			contents = append(contents, "import "+imp+";")
+		}
+		if javaImportSet.Size() > 0 {
+			contents = append(contents, "")
+			for _, imp := range javaImportSet.Slice() {
+				contents = append(contents, "import "+imp+";")
+			}
+		}
+		contents = append(contents, "")
+		contents = append(contents, lines...)
+
+		// Write file
+		if err := k.writeLines(path.Join(entityDir, class.Name+".java"), contents); err != nil {
+			return err
+		}
+
+		// Write Repos
+		if reposDir != "" && idClassName == "" {
+			log.Printf("Repository of %s is not generated. primary key is required", table.Name)
+		} else if reposDir != "" {
+			reposClassName := fmt.Sprintf("%sRepository", class.Name)
+			reposLines := []string{
+				fmt.Sprintf("package %s;", reposPackage),
+				"",
+			}
+			if outputPackage != "" {
+				reposLines = append(reposLines, fmt.Sprintf("import %s.%s;", outputPackage, class.Name))
+			}
+			reposLines = append(reposLines,
+				"import org.springframework.data.jpa.repository.JpaRepository;",
+				"import org.springframework.stereotype.Repository;",
+			)
+			finderLines, finderImports := j.repositoryFinderLines(class, indent)
+			reposImportSet := NewStringSet(finderImports...)
+			if len(class.PKFields) == 1 {
+				reposImportSet.AddAll(class.PKFields[0].Imports)
+			}
+			if reposImportSet.Size() > 0 {
+				reposLines = append(reposLines, "")
+				for _, imp := range reposImportSet.Slice() {
+					reposLines = append(reposLines, "import "+imp+";")
+				}
+			}
+			reposLines = append(reposLines,
+				"",
+				"@Repository",
+				fmt.Sprintf("public interface %s extends JpaRepository<%s, %s> {", reposClassName, class.Name, idClassName),
+			)
+			reposLines = append(reposLines, finderLines...)
+			reposLines = append(reposLines, "}", "")
+			if err := k.writeLines(path.Join(reposDir, reposClassName+".java"), reposLines); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// repositoryFinderLines returns java repository methods and imports.
+func (j *JPAJava) repositoryFinderLines(class *JavaClass, indent string) ([]string, []string) {
+	fieldByColumn := make(map[*Column]*JavaField)
+	for _, field := range class.Fields {
+		fieldByColumn[field.Column] = field
+	}
+
+	lines := make([]string, 0)
+	importSet := NewStringSet()
+	for _, finder := range newRepositoryFinders(class.table) {
+		properties := make([]string, 0)
+		params := make([]string, 0)
+		for _, column := range finder.columns {
+			field := fieldByColumn[column]
+			properties = append(properties, strcase.ToCamel(field.Name))
+			params = append(params, field.Type+" "+field.Name)
+			importSet.AddAll(field.Imports)
+		}
+		by := strings.Join(properties, "And")
+		paramList := strings.Join(params, ", ")
+
+		if finder.unique {
+			importSet.Add("java.util.Optional")
+			lines = append(lines,
+				indent+fmt.Sprintf("Optional<%s> findBy%s(%s);", class.Name, by, paramList),
+				"",
+				indent+fmt.Sprintf("boolean existsBy%s(%s);", by, paramList),
+				"")
+		} else {
+			importSet.Add("java.util.List")
+			lines = append(lines,
+				indent+fmt.Sprintf("List<%s> findAllBy%s(%s);", class.Name, by, paramList),
+				"")
+		}
+	}
+	if len(lines) > 0 {
+		lines = lines[:len(lines)-1]
+	}
+	return lines, importSet.Slice()
+}
+
+// accessors returns getter and setter methods of fields.
+func (j *JPAJava) accessors(fields []*JavaField, indent string) []string {
+	lines := make([]string, 0)
+	for _, field := range fields {
+		upperName := strcase.ToCamel(field.Name)
+		lines = append(lines,
+			indent+fmt.Sprintf("public %s get%s() {", field.Type, upperName),
+			indent+indent+fmt.Sprintf("return %s;", field.Name),
+			indent+"}",
+			"",
+			indent+fmt.Sprintf("public void set%s(%s %s) {", upperName, field.Type, field.Name),
+			indent+indent+fmt.Sprintf("this.%s = %s;", field.Name, field.Name),
+			indent+"}",
+			"",
+		)
+	}
+	return lines
+}
+
+// idClass returns static nested class of composite primary key.
+func (j *JPAJava) idClass(class *JavaClass, indent string, useLombok bool) []string {
+	lines := make([]string, 0)
+	appendLine := func(line string) {
+		if line == "" {
+			lines = append(lines, "")
+		} else {
+			lines = append(lines, indent+line)
+		}
+	}
+
+	if useLombok {
+		appendLine("@Getter")
+		appendLine("@Setter")
+	}
+	appendLine("public static class PK implements Serializable {")
+	params := make([]string, 0)
+	for _, field := range class.PKFields {
+		appendLine(indent + fmt.Sprintf("private %s %s;", field.Type, field.Name))
+		params = append(params, field.Type+" "+field.Name)
+	}
+	appendLine("")
+
+	// constructors
+	appendLine(indent + "public PK() {")
+	appendLine(indent + "}")
+	appendLine("")
+	appendLine(indent + fmt.Sprintf("public PK(%s) {", strings.Join(params, ", ")))
+	for _, field := range class.PKFields {
+		appendLine(indent + indent + fmt.Sprintf("this.%s = %s;", field.Name, field.Name))
+	}
+	appendLine(indent + "}")
+	appendLine("")
+
+	if !useLombok {
+		for _, line := range j.accessors(class.PKFields, indent) {
+			appendLine(line)
+		}
+	}
+
+	// equals, hashCode
+	names := make([]string, 0)
+	conditions := make([]string, 0)
+	for _, field := range class.PKFields {
+		names = append(names, field.Name)
+		conditions = append(conditions, fmt.Sprintf("Objects.equals(%s, pk.%s)", field.Name, field.Name))
+	}
+	appendLine(indent + "@Override")
+	appendLine(indent + "public boolean equals(Object o) {")
+	appendLine(indent + indent + "if (this == o) return true;")
+	appendLine(indent + indent + "if (o == null || getClass() != o.getClass()) return false;")
+	appendLine(indent + indent + "PK pk = (PK) o;")
+	appendLine(indent + indent + fmt.Sprintf("return %s;", strings.Join(conditions, " && ")))
+	appendLine(indent + "}")
+	appendLine("")
+	appendLine(indent + "@Override")
+	appendLine(indent + "public int hashCode() {")
+	appendLine(indent + indent + fmt.Sprintf("return Objects.hash(%s);", strings.Join(names, ", ")))
+	appendLine(indent + "}")
+	appendLine("}")
+	return lines
+}
@@ -0,0 +1,246 @@
+package main
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"strings"
+	"testing"
+)
+
+func TestJPAJava_Generate_CompositeKey(t *testing.T) {
+	schema := &Schema{
+		Tables: []*Table{
+			{
+				Name: "user_role",
+				Columns: []*Column{
+					{Name: "user_id", Type: ColTypeLong, PrimaryKey: true},
+					{Name: "role", Type: ColTypeString, Size: 20, PrimaryKey: true},
+					{Name: "expired_at", Type: ColTypeDateTime, Nullable: true},
+				},
+			},
+		},
+	}
+
+	files, err := generateOutput(t, schema, &Output{Format: FormatJpaJava, Options: map[string]string{
+		FlagPackage:      "com.example.entity",
+		FlagReposPackage: "com.example.repos",
+		FlagLombok:       "true",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := strings.Join([]string{
+		"package com.example.entity;",
+		"",
+		"import javax.persistence.*;",
+		"import lombok.Getter;",
+		"import lombok.Setter;",
+		"",
+		"import java.io.Serializable;",
+		"import java.sql.Timestamp;",
+		"import java.util.Objects;",
+		"",
+		"@Entity",
+		"@Table(name = \"user_role\")",
+		"@IdClass(UserRole.PK.class)",
+		"@Getter",
+		"@Setter",
+		"public class UserRole {",
+		"    @Id",
+		"    @Column(nullable = false)",
+		"    private Long userId;",
+		"",
+		"    @Id",
+		"    @Column(nullable = false, length = 20)",
+		"    private String role;",
+		"",
+		"    private Timestamp expiredAt;",
+		"",
+		"    @Getter",
+		"    @Setter",
+		"    public static class PK implements Serializable {",
+		"        private Long userId;",
+		"        private String role;",
+		"",
+		"        public PK() {",
+		"        }",
+		"",
+		"        public PK(Long userId, String role) {",
+		"            this.userId = userId;",
+		"            this.role = role;",
+		"        }",
+		"",
+		"        @Override",
+		"        public boolean equals(Object o) {",
+		"            if (this == o) return true;",
+		"            if (o == null || getClass() != o.getClass()) return false;",
+		"            PK pk = (PK) o;",
+		"            return Objects.equals(userId, pk.userId) && Objects.equals(role, pk.role);",
+		"        }",
+		"",
+		"        @Override",
+		"        public int hashCode() {",
+		"            return Objects.hash(userId, role);",
+		"        }",
+		"    }",
+		"}",
+		"",
+	}, "\n")
+	if diff := cmp.Diff(expected, files["com/example/entity/UserRole.java"]); diff != "" {
+		t.Errorf("TestJPAJava_Generate_CompositeKey() entity mismatch (-expected +actual):\n%s", diff)
+	}
+
+	expectedRepos := strings.Join([]string{
+		"package com.example.repos;",
+		"",
+		"import com.example.entity.UserRole;",
+		"import org.springframework.data.jpa.repository.JpaRepository;",
+		"import org.springframework.stereotype.Repository;",
+		"",
+		"@Repository",
+		"public interface UserRoleRepository extends JpaRepository<UserRole, UserRole.PK> {",
+		"}",
+		"",
+	}, "\n")
+	if diff := cmp.Diff(expectedRepos, files["com/example/repos/UserRoleRepository.java"]); diff != "" {
+		t.Errorf("TestJPAJava_Generate_CompositeKey() repository mismatch (-expected +actual):\n%s", diff)
+	}
+}
+
+func TestJPAJava_Generate_EmptyPackage(t *testing.T) {
+	schema := &Schema{
+		Tables: []*Table{
+			{
+				Name: "user",
+				Columns: []*Column{
+					{Name: "id", Type: ColTypeLong, PrimaryKey: true, AutoIncremental: true},
+					{Name: "userName", Type: ColTypeString, Nullable: true},
+				},
+			},
+		},
+	}
+
+	files, err := generateOutput(t, schema, &Output{Format: FormatJpaJava, Options: map[string]string{
+		FlagReposPackage: "com.example.repos",
+		FlagDbms:         DbmsMysql,
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// entity is written to output directory without package
+	expected := strings.Join([]string{
+		"import javax.persistence.*;",
+		"",
+		"@Entity",
+		"@Table(name = \"user\")",
+		"public class User {",
+		"    @Id",
+		"    @GeneratedValue(strategy = GenerationType.IDENTITY)",
+		"    @Column(nullable = false)",
+		"    private Long id;",
+		"",
+		"    @Column(name = \"userName\")",
+		"    private String userName;",
+		"",
+		"    public Long getId() {",
+		"        return id;",
+		"    }",
+		"",
+		"    public void setId(Long id) {",
+		"        this.id = id;",
+		"    }",
+		"",
+		"    public String getUserName() {",
+		"        return userName;",
+		"    }",
+		"",
+		"    public void setUserName(String userName) {",
+		"        this.userName = userName;",
+		"    }",
+		"}",
+		"",
+	}, "\n")
+	if diff := cmp.Diff(expected, files["User.java"]); diff != "" {
+		t.Errorf("TestJPAJava_Generate_EmptyPackage() entity mismatch (-expected +actual):\n%s", diff)
+	}
+
+	expectedRepos := strings.Join([]string{
+		"package com.example.repos;",
+		"",
+		"import org.springframework.data.jpa.repository.JpaRepository;",
+		"import org.springframework.stereotype.Repository;",
+		"",
+		"@Repository",
+		"public interface UserRepository extends JpaRepository<User, Long> {",
+		"}",
+		"",
+	}, "\n")
+	if diff := cmp.Diff(expectedRepos, files["com/example/repos/UserRepository.java"]); diff != "" {
+		t.Errorf("TestJPAJava_Generate_EmptyPackage() repository mismatch (-expected +actual):\n%s", diff)
+	}
+}
+
+func TestJPAJava_Generate_Repository(t *testing.T) {
+	files, err := generateOutput(t, newRepositoryTestSchema(), &Output{Format: FormatJpaJava, Options: map[string]string{
+		FlagPackage:      "com.example.entity",
+		FlagReposPackage: "com.example.repos",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := strings.Join([]string{
+		"package com.example.repos;",
+		"",
+		"import com.example.entity.User;",
+		"import org.springframework.data.jpa.repository.JpaRepository;",
+		"import org.springframework.stereotype.Repository;",
+		"",
+		"import java.sql.Timestamp;",
+		"import java.util.List;",
+		"import java.util.Optional;",
+		"",
+		"@Repository",
+		"public interface UserRepository extends JpaRepository<User, Long> {",
+		"    Optional<User> findByEmail(String email);",
+		"",
+		"    boolean existsByEmail(String email);",
+		"",
+		"    List<User> findAllByGroupId(Long groupId);",
+		"",
+		"    List<User> findAllByNameAndCreatedAt(String name, Timestamp createdAt);",
+		"}",
+		"",
+	}, "\n")
+	if diff := cmp.Diff(expected, files["com/example/repos/UserRepository.java"]); diff != "" {
+		t.Errorf("TestJPAJava_Generate_Repository() mismatch (-expected +actual):\n%s", diff)
+	}
+}
+
+func TestJPAJava_Generate_RepositoryWithoutPK(t *testing.T) {
+	schema := &Schema{
+		Tables: []*Table{
+			{
+				Name:    "access_log",
+				Columns: []*Column{{Name: "path", Type: ColTypeString, Size: 100}},
+			},
+		},
+	}
+
+	files, err := generateOutput(t, schema, &Output{Format: FormatJpaJava, Options: map[string]string{
+		FlagPackage:      "com.example.entity",
+		FlagReposPackage: "com.example.repos",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// repository is not generated without primary key
+	if _, ok := files["com/example/entity/AccessLog.java"]; !ok {
+		t.Errorf("TestJPAJava_Generate_RepositoryWithoutPK() entity not found: %v", files)
+	}
+	if _, ok := files["com/example/repos/AccessLogRepository.java"]; ok {
+		t.Errorf("TestJPAJava_Generate_RepositoryWithoutPK() repository is generated")
+	}
+}
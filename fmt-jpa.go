@@ -11,13 +11,25 @@ import (
 	"strings"
 )
 
+const (
+	JpaRelationVRelation = "VRelation"
+	JpaRelationManyToOne = "ManyToOne"
+
+	JpaPersistenceJavax   = "javax"
+	JpaPersistenceJakarta = "jakarta"
+)
+
 type KotlinClass struct {
-	table        *Table
-	Name         string
-	Annotations  []string
-	Fields       []*KotlinField
-	PKFields     []*KotlinField
-	UniqueFields []*KotlinField
+	table            *Table
+	Name             string
+	Annotations      []string
+	Imports          []string
+	Fields           []*KotlinField
+	PKFields         []*KotlinField
+	UniqueFields     []*KotlinField
+	OneToManyFields  []*KotlinOneToManyField
+	ManyToManyFields []*KotlinManyToManyField
+	EmbeddedFields   []*KotlinEmbeddedField
 }
 
 type KotlinField struct {
@@ -27,17 +39,62 @@ type KotlinField struct {
 	Type         string
 	Imports      []string
 	DefaultValue string
+	Annotations  []string
+	// referenced class of @ManyToOne field
+	RefClass *KotlinClass
+}
+
+// KotlinOneToManyField is a collection field of the inverse side of @ManyToOne.
+type KotlinOneToManyField struct {
+	Name     string
+	Class    *KotlinClass
+	MappedBy string
+}
+
+// KotlinManyToManyField is a collection field mapped by a join table.
+// owning side has JoinTable, and inverse side has MappedBy.
+type KotlinManyToManyField struct {
+	Name              string
+	Class             *KotlinClass
+	MappedBy          string
+	JoinTable         string
+	JoinColumn        string
+	InverseJoinColumn string
+}
+
+// KotlinEmbeddedField is an @Embedded field of columns included by embed.
+type KotlinEmbeddedField struct {
+	Name      string
+	ClassName string
+	Embed     *Embed
+	Columns   []*Column
+}
+
+// Nullable returns true if all columns are nullable.
+func (f *KotlinEmbeddedField) Nullable() bool {
+	for _, column := range f.Columns {
+		if !column.Nullable {
+			return false
+		}
+	}
+	return true
+}
+
+// HasDefaultValues returns true if all fields of embeddable class have default values.
+func (f *KotlinEmbeddedField) HasDefaultValues() bool {
+	for _, column := range f.Columns {
+		if !column.EmbeddableColumn.Nullable && NewKotlinField(column.EmbeddableColumn).DefaultValue == "" {
+			return false
+		}
+	}
+	return true
 }
 
 type JPAKotlin struct {
 }
 
-func NewKotlinClass(
-	table *Table,
-	output *Output,
-	annoMapper *AnnotationMapper,
-	prefixMapper *PrefixMapper,
-) *KotlinClass {
+// jpaClassName returns entity class name of the table.
+func jpaClassName(table *Table, output *Output, prefixMapper *PrefixMapper) string {
 	className := table.ClassName
 	if className == "" {
 		tableName := table.Name
@@ -50,11 +107,43 @@ func NewKotlinClass(
 			className = prefix + className
 		}
 	}
+	return className
+}
+
+func NewKotlinClass(
+	table *Table,
+	output *Output,
+	annoMapper *AnnotationMapper,
+	prefixMapper *PrefixMapper,
+) *KotlinClass {
+	className := jpaClassName(table, output, prefixMapper)
+	target := table.Target(FormatJpaKotlin)
+	if target.Name != "" {
+		className = target.Name
+	}
 
 	fields := make([]*KotlinField, 0)
 	pkFields := make([]*KotlinField, 0)
 	uniqueFields := make([]*KotlinField, 0)
+	embeddedFields := make([]*KotlinEmbeddedField, 0)
+	embeddedFieldByEmbed := make(map[*Embed]*KotlinEmbeddedField)
 	for _, column := range table.Columns {
+		// columns of embed
+		if embed := column.Embed; embed != nil {
+			embeddedField, ok := embeddedFieldByEmbed[embed]
+			if !ok {
+				embeddedField = &KotlinEmbeddedField{
+					Name:      embed.FieldName(),
+					ClassName: strcase.ToCamel(embed.Embeddable),
+					Embed:     embed,
+				}
+				embeddedFields = append(embeddedFields, embeddedField)
+				embeddedFieldByEmbed[embed] = embeddedField
+			}
+			embeddedField.Columns = append(embeddedField.Columns, column)
+			continue
+		}
+
 		field := NewKotlinField(column)
 		fields = append(fields, field)
 
@@ -66,13 +155,18 @@ func NewKotlinClass(
 		}
 	}
 
+	annotations := append([]string{}, annoMapper.GetAnnotations(table.Group)...)
+	annotations = append(annotations, target.Annotations...)
+
 	return &KotlinClass{
-		table:        table,
-		Name:         className,
-		Annotations:  annoMapper.GetAnnotations(table.Group),
-		Fields:       fields,
-		PKFields:     pkFields,
-		UniqueFields: uniqueFields,
+		table:          table,
+		Name:           className,
+		Annotations:    annotations,
+		Imports:        target.Imports,
+		Fields:         fields,
+		PKFields:       pkFields,
+		UniqueFields:   uniqueFields,
+		EmbeddedFields: embeddedFields,
 	}
 }
 
@@ -145,6 +239,15 @@ func NewKotlinField(column *Column) *KotlinField {
 		fieldType = "Blob"
 		importSet.Add("java.sql.Blob")
 	default:
+		if columnType == "uuid" {
+			fieldType = "UUID"
+			importSet.Add("java.util.UUID")
+			break
+		}
+		if columnType == "json" {
+			fieldType = "String"
+			break
+		}
 		if columnType == "bit" {
 			if column.Size == 1 {
 				fieldType = "Boolean"
@@ -162,6 +265,22 @@ func NewKotlinField(column *Column) *KotlinField {
 
 	fieldName, ok := ToLowerCamel(column.Name)
 
+	// override by target
+	target := column.Target(FormatJpaKotlin)
+	if target.Name != "" {
+		fieldName = target.Name
+		ok = false
+	}
+	if target.Type != "" {
+		fieldType = target.Type
+		if nullable && !strings.HasSuffix(fieldType, "?") {
+			fieldType = fieldType + "?"
+		}
+		defaultValue = ""
+		importSet.Clear()
+	}
+	importSet.AddAll(target.Imports)
+
 	return &KotlinField{
 		Column:       column,
 		Name:         fieldName,
@@ -169,7 +288,273 @@ func NewKotlinField(column *Column) *KotlinField {
 		Type:         fieldType,
 		DefaultValue: defaultValue,
 		Imports:      importSet.Slice(),
+		Annotations:  target.Annotations,
+	}
+}
+
+// resolveRelations replaces reference columns with @ManyToOne fields of the referenced class,
+// and adds @OneToMany fields to the referenced class if oneToMany is set.
+// primary key columns are kept as they are.
+func (k *JPAKotlin) resolveRelations(classes []*KotlinClass, oneToMany bool, ignoreUnknownRelation bool) error {
+	classByTable := make(map[string]*KotlinClass)
+	for _, class := range classes {
+		classByTable[class.table.Name] = class
+	}
+
+	client := pluralize.NewClient()
+	for _, class := range classes {
+		fieldNameSet := NewStringSet()
+		for _, field := range class.Fields {
+			fieldNameSet.Add(field.Name)
+		}
+
+		for _, field := range class.Fields {
+			ref := field.Column.Ref
+			if ref == nil || field.Column.PrimaryKey {
+				continue
+			}
+			refClass, ok := classByTable[ref.Table]
+			if !ok {
+				if !ignoreUnknownRelation {
+					return fmt.Errorf("relation not found. %s::%s -> %s", class.Name, field.Name, ref.Table)
+				}
+				log.Printf("Relation not found. %s::%s -> %s\n", class.Name, field.Name, ref.Table)
+				continue
+			}
+
+			fieldNameSet.Remove(field.Name)
+			name := refFieldName(fieldNameSet, field.Column, refClass.Name)
+			fieldNameSet.Add(name)
+
+			field.Name = name
+			field.OverrideName = false
+			field.Type = refClass.Name + "?"
+			field.DefaultValue = ""
+			field.Imports = []string{}
+			field.RefClass = refClass
+
+			if oneToMany {
+				collectionName := client.Plural(strcase.ToLowerCamel(class.Name))
+				for _, oneToManyField := range refClass.OneToManyFields {
+					if oneToManyField.Name == collectionName {
+						collectionName = collectionName + "By" + strcase.ToCamel(name)
+						break
+					}
+				}
+				refClass.OneToManyFields = append(refClass.OneToManyFields, &KotlinOneToManyField{
+					Name:     collectionName,
+					Class:    class,
+					MappedBy: name,
+				})
+			}
+		}
+	}
+	return nil
+}
+
+// isJoinTable checks if the class is a pure join table,
+// which has two reference columns forming the composite primary key and no other columns.
+func (k *JPAKotlin) isJoinTable(class *KotlinClass, classByTable map[string]*KotlinClass) bool {
+	if len(class.Fields) != 2 || len(class.PKFields) != 2 {
+		return false
+	}
+	for _, field := range class.Fields {
+		ref := field.Column.Ref
+		if ref == nil {
+			return false
+		}
+		if _, ok := classByTable[ref.Table]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// resolveManyToMany adds @ManyToMany fields to classes related by pure join tables.
+// returns classes except join tables.
+func (k *JPAKotlin) resolveManyToMany(classes []*KotlinClass) []*KotlinClass {
+	classByTable := make(map[string]*KotlinClass)
+	for _, class := range classes {
+		classByTable[class.table.Name] = class
+	}
+
+	// collection name is derived from class name, or join column name if duplicated.
+	client := pluralize.NewClient()
+	collectionName := func(owner *KotlinClass, target *KotlinClass, column *Column) string {
+		nameSet := NewStringSet()
+		for _, field := range owner.Fields {
+			nameSet.Add(field.Name)
+		}
+		for _, field := range owner.OneToManyFields {
+			nameSet.Add(field.Name)
+		}
+		for _, field := range owner.ManyToManyFields {
+			nameSet.Add(field.Name)
+		}
+
+		name := client.Plural(strcase.ToLowerCamel(target.Name))
+		if nameSet.Contains(name) {
+			name = client.Plural(strcase.ToLowerCamel(strings.TrimSuffix(strings.ToLower(column.Name), "_id")))
+		}
+		if nameSet.Contains(name) {
+			name = name + "By" + strcase.ToCamel(column.Name)
+		}
+		return name
+	}
+
+	result := make([]*KotlinClass, 0)
+	for _, class := range classes {
+		if !k.isJoinTable(class, classByTable) {
+			result = append(result, class)
+			continue
+		}
+
+		joinColumn := class.Fields[0].Column
+		inverseJoinColumn := class.Fields[1].Column
+		owner := classByTable[joinColumn.Ref.Table]
+		inverse := classByTable[inverseJoinColumn.Ref.Table]
+
+		ownerField := &KotlinManyToManyField{
+			Name:              collectionName(owner, inverse, inverseJoinColumn),
+			Class:             inverse,
+			JoinTable:         class.table.Name,
+			JoinColumn:        joinColumn.Name,
+			InverseJoinColumn: inverseJoinColumn.Name,
+		}
+		owner.ManyToManyFields = append(owner.ManyToManyFields, ownerField)
+
+		inverse.ManyToManyFields = append(inverse.ManyToManyFields, &KotlinManyToManyField{
+			Name:     collectionName(inverse, owner, joinColumn),
+			Class:    owner,
+			MappedBy: ownerField.Name,
+		})
+		log.Printf("join table %s is generated as @ManyToMany of %s and %s", class.table.Name, owner.Name, inverse.Name)
+	}
+	return result
+}
+
+// refFieldName returns field name of referenced class.
+// field name is derived from referenced class name, or column name if duplicated.
+func refFieldName(fieldNameSet *StringSet, column *Column, refClassName string) string {
+	name := strcase.ToLowerCamel(refClassName)
+	if fieldNameSet.Contains(name) {
+		name = strcase.ToLowerCamel(strings.TrimSuffix(strings.ToLower(column.Name), "_id"))
+	}
+	if fieldNameSet.Contains(name) {
+		name = name + refClassName
+	}
+	return name
+}
+
+// getCascadeTypes returns kotlin array of CascadeType. e.g. [CascadeType.PERSIST, CascadeType.MERGE]
+func getCascadeTypes(cascade string) string {
+	cascadeTypes := make([]string, 0)
+	for _, cascadeType := range strings.Split(cascade, ",") {
+		if cascadeType = strings.ToUpper(strings.TrimSpace(cascadeType)); cascadeType != "" {
+			cascadeTypes = append(cascadeTypes, "CascadeType."+cascadeType)
+		}
+	}
+	if len(cascadeTypes) == 0 {
+		return ""
+	}
+	return "[" + strings.Join(cascadeTypes, ", ") + "]"
+}
+
+// repositoryFinder is a derived query method of spring data repository.
+// unique finder returns single entity, otherwise returns list of entities.
+type repositoryFinder struct {
+	unique  bool
+	columns []*Column
+}
+
+// newRepositoryFinders returns finders of unique key, reference columns and indices.
+func newRepositoryFinders(table *Table) []*repositoryFinder {
+	result := make([]*repositoryFinder, 0)
+	columnsSet := NewStringSet()
+	addFinder := func(unique bool, columns []*Column) {
+		names := make([]string, 0)
+		for _, column := range columns {
+			names = append(names, column.Name)
+		}
+		if key := strings.Join(names, ","); len(columns) > 0 && !columnsSet.Contains(key) {
+			columnsSet.Add(key)
+			result = append(result, &repositoryFinder{unique: unique, columns: columns})
+		}
+	}
+
+	// all unique columns form a unique constraint
+	uniqueColumns := make([]*Column, 0)
+	for _, column := range table.Columns {
+		if column.UniqueKey && !column.PrimaryKey && column.Embed == nil {
+			uniqueColumns = append(uniqueColumns, column)
+		}
+	}
+	addFinder(true, uniqueColumns)
+
+	for _, column := range table.Columns {
+		if column.Ref != nil && !column.PrimaryKey && column.Embed == nil {
+			addFinder(false, []*Column{column})
+		}
+	}
+
+	columnByName := table.ColumnByName()
+	for _, index := range table.Indices {
+		columns := make([]*Column, 0)
+		for _, columnName := range index.Columns {
+			if column, ok := columnByName[columnName]; ok && column.Embed == nil {
+				columns = append(columns, column)
+			}
+		}
+		if len(columns) == len(index.Columns) {
+			addFinder(false, columns)
+		}
 	}
+	return result
+}
+
+// repositoryFinderLines returns kotlin repository methods and imports.
+func (k *JPAKotlin) repositoryFinderLines(class *KotlinClass) ([]string, []string) {
+	fieldByColumn := make(map[*Column]*KotlinField)
+	for _, field := range class.Fields {
+		fieldByColumn[field.Column] = field
+	}
+
+	lines := make([]string, 0)
+	importSet := NewStringSet()
+	for _, finder := range newRepositoryFinders(class.table) {
+		properties := make([]string, 0)
+		params := make([]string, 0)
+		for _, column := range finder.columns {
+			// @ManyToOne field is queried by the property of referenced class. e.g. group.id
+			property := fieldByColumn[column].Name
+			if field := fieldByColumn[column]; field.RefClass != nil {
+				property += strcase.ToCamel(column.Ref.Column)
+			}
+			paramField := NewKotlinField(column)
+			paramType := strings.TrimSuffix(paramField.Type, "?")
+			importSet.AddAll(paramField.Imports)
+			properties = append(properties, strcase.ToCamel(property))
+			params = append(params, fmt.Sprintf("%s: %s", strcase.ToLowerCamel(property), paramType))
+		}
+		by := strings.Join(properties, "And")
+		paramList := strings.Join(params, ", ")
+
+		if finder.unique {
+			lines = append(lines,
+				fmt.Sprintf("    fun findBy%s(%s): %s?", by, paramList, class.Name),
+				"",
+				fmt.Sprintf("    fun existsBy%s(%s): Boolean", by, paramList),
+				"")
+		} else {
+			lines = append(lines,
+				fmt.Sprintf("    fun findAllBy%s(%s): List<%s>", by, paramList, class.Name),
+				"")
+		}
+	}
+	if len(lines) > 0 {
+		lines = lines[:len(lines)-1]
+	}
+	return lines, importSet.Slice()
 }
 
 func (k *JPAKotlin) mkdir(basedir, pkgName string) (string, error) {
@@ -195,11 +580,46 @@ func (k *JPAKotlin) Generate(
 	outputPackage := output.Get(FlagPackage)
 	reposPackage := output.Get(FlagReposPackage)
 	graphqlPackage := output.Get(FlagGraphqlPackage)
+	graphqlFramework, err := getGraphqlFramework(output)
+	if err != nil {
+		return err
+	}
+	if graphqlPackage != "" && graphqlFramework != GraphqlFrameworkCoxautodev && reposPackage == "" {
+		return fmt.Errorf("reposPackage is required for %s resolvers", graphqlFramework)
+	}
 	relation := output.Get(FlagRelation)
 	ignoreUnknownRelation := output.Get(FlagIgnoreUnknownRelation)
 	uniqueNameSuffix := output.Get(FlagUniqueNameSuffix)
 	idEntityInterfaceName := output.Get(FlagIdEntity)
 	useDefaultNull := output.Get(FlagUseDefaultNull)
+	fetchType := strings.ToUpper(output.Get(FlagFetch))
+	if fetchType == "" {
+		fetchType = "LAZY"
+	}
+	if fetchType != "LAZY" && fetchType != "EAGER" {
+		return fmt.Errorf("unsupported fetch type: %s", output.Get(FlagFetch))
+	}
+	cascadeTypes := getCascadeTypes(output.Get(FlagCascade))
+	audit, err := newAuditColumns(output.Get(FlagAudit))
+	if err != nil {
+		return err
+	}
+
+	// jakarta: Spring Boot 3 and Hibernate 6
+	persistence := output.Get(FlagPersistence)
+	if persistence == "" {
+		persistence = JpaPersistenceJavax
+	}
+	if persistence != JpaPersistenceJavax && persistence != JpaPersistenceJakarta {
+		return fmt.Errorf("unsupported persistence: %s", persistence)
+	}
+	isJakarta := persistence == JpaPersistenceJakarta
+
+	// AUTO uses table generator on MySQL since Hibernate 5
+	generationType := "AUTO"
+	if dbms := strings.ToLower(output.Get(FlagDbms)); dbms == DbmsMysql || dbms == "mariadb" {
+		generationType = "IDENTITY"
+	}
 
 	entityDir, err := k.mkdir(output.FilePath, outputPackage)
 	if err != nil {
@@ -216,7 +636,7 @@ func (k *JPAKotlin) Generate(
 
 	if !useDataClass {
 		// Generate AbstractJpaPersistable.kt
-		if err := k.generateAbstractJpaPersistable(entityDir, outputPackage); err != nil {
+		if err := k.generateAbstractJpaPersistable(entityDir, outputPackage, persistence); err != nil {
 			return err
 		}
 	}
@@ -232,6 +652,15 @@ func (k *JPAKotlin) Generate(
 		classes = append(classes, NewKotlinClass(table, output, annoMapper, prefixMapper))
 	}
 
+	if output.GetBool(FlagManyToMany) {
+		classes = k.resolveManyToMany(classes)
+	}
+	if relation == JpaRelationManyToOne {
+		if err := k.resolveRelations(classes, output.GetBool(FlagOneToMany), ignoreUnknownRelation == "true"); err != nil {
+			return err
+		}
+	}
+
 	getClassNameByTable := func(table string) string {
 		for _, cls := range classes {
 			if cls.table.Name == table {
@@ -265,13 +694,18 @@ func (k *JPAKotlin) Generate(
 		}
 		importSet := NewStringSet()
 		javaImportSet := NewStringSet()
-		javaImportSet.Add("javax.persistence.*")
+		javaImportSet.Add(persistence + ".persistence.*")
+		importSet.AddAll(class.Imports)
 
 		// unique
 		uniqueCstName := table.Name + uniqueNameSuffix
 		uniqueFieldNames := make([]string, 0)
 		for _, field := range class.UniqueFields {
-			uniqueFieldNames = append(uniqueFieldNames, Quote(field.Name, "\""))
+			if field.RefClass != nil {
+				uniqueFieldNames = append(uniqueFieldNames, Quote(field.Column.Name, "\""))
+			} else {
+				uniqueFieldNames = append(uniqueFieldNames, Quote(field.Name, "\""))
+			}
 		}
 
 		// class
@@ -290,6 +724,11 @@ func (k *JPAKotlin) Generate(
 			idClassName = class.Name + "PK"
 			appendLine(fmt.Sprintf("@IdClass(%s::class)", idClassName))
 		}
+		auditAnnotations, auditImports := audit.jpaClassAnnotations(table, "::class", persistence)
+		for _, anno := range auditAnnotations {
+			appendLine(anno)
+		}
+		importSet.AddAll(auditImports)
 
 		classDef := fmt.Sprintf("class %s(", class.Name)
 		if useDataClass {
@@ -299,9 +738,40 @@ func (k *JPAKotlin) Generate(
 		}
 
 		// fields
-		fieldCount := len(class.Fields)
+		fieldCount := len(class.Fields) + len(class.EmbeddedFields)
 		for i, field := range class.Fields {
 			column := field.Column
+
+			// annotations of target
+			for _, anno := range field.Annotations {
+				appendLine(indent + anno)
+			}
+
+			// @ManyToOne
+			if field.RefClass != nil {
+				joinColumn := fmt.Sprintf("name = \"%s\"", column.Name)
+				// referenced column is set unless it's the primary key of the referenced class
+				refPKFields := field.RefClass.PKFields
+				if len(refPKFields) != 1 || refPKFields[0].Column.Name != column.Ref.Column {
+					joinColumn += fmt.Sprintf(", referencedColumnName = \"%s\"", column.Ref.Column)
+				}
+				if column.Nullable {
+					appendLine(indent + fmt.Sprintf("@ManyToOne(fetch = FetchType.%s)", fetchType))
+					appendLine(indent + fmt.Sprintf("@JoinColumn(%s)", joinColumn))
+				} else {
+					appendLine(indent + fmt.Sprintf("@ManyToOne(fetch = FetchType.%s, optional = false)", fetchType))
+					appendLine(indent + fmt.Sprintf("@JoinColumn(%s, nullable = false)", joinColumn))
+				}
+				line := fmt.Sprintf("var %s: %s = null", field.Name, field.Type)
+				if i < fieldCount-1 {
+					appendLine(indent + line + ",")
+					appendLine("")
+				} else {
+					appendLine(indent + line)
+				}
+				continue
+			}
+
 			if column.PrimaryKey {
 				appendLine(indent + "@Id")
 				if idClassName == "" {
@@ -309,14 +779,26 @@ func (k *JPAKotlin) Generate(
 				}
 			}
 			if column.AutoIncremental {
-				appendLine(indent + "@GeneratedValue(strategy = GenerationType.AUTO)")
+				appendLine(indent + fmt.Sprintf("@GeneratedValue(strategy = GenerationType.%s)", generationType))
+			} else if column.PrimaryKey && column.Type == "uuid" && pkFieldCount == 1 {
+				if isJakarta {
+					appendLine(indent + "@UuidGenerator")
+					importSet.Add("org.hibernate.annotations.UuidGenerator")
+				} else {
+					appendLine(indent + "@GeneratedValue")
+				}
 			}
 			if column.Type == "text" {
 				appendLine(indent + "@Lob")
 			}
+			if column.Type == "json" && isJakarta {
+				appendLine(indent + "@JdbcTypeCode(SqlTypes.JSON)")
+				importSet.Add("org.hibernate.annotations.JdbcTypeCode")
+				importSet.Add("org.hibernate.type.SqlTypes")
+			}
 
 			// @VRelation
-			if relation == "VRelation" {
+			if relation == JpaRelationVRelation {
 				if ref := column.Ref; ref != nil {
 					targetClassName := getClassNameByTable(ref.Table)
 					if len(targetClassName) == 0 {
@@ -357,17 +839,15 @@ func (k *JPAKotlin) Generate(
 					attributes = append(attributes, fmt.Sprintf("scale = %d", column.Scale))
 				}
 			}
-			// @CreationTimestamp
-			if column.Type == "datetime" && field.Name == "createdAt" {
-				appendLine(indent + "@CreationTimestamp")
-				attributes = append(attributes, "updatable = false")
-				importSet.Add("org.hibernate.annotations.CreationTimestamp")
+			// audit
+			auditAnnotations, notUpdatable, auditImports := audit.jpaFieldAnnotations(column)
+			for _, anno := range auditAnnotations {
+				appendLine(indent + anno)
 			}
-			// @UpdateTimestamp
-			if column.Type == "datetime" && field.Name == "updatedAt" {
-				appendLine(indent + "@UpdateTimestamp")
-				importSet.Add("org.hibernate.annotations.UpdateTimestamp")
+			if notUpdatable {
+				attributes = append(attributes, "updatable = false")
 			}
+			importSet.AddAll(auditImports)
 			if len(attributes) > 0 {
 				appendLine(indent + fmt.Sprintf("@Column(%s)", strings.Join(attributes, ", ")))
 			}
@@ -405,15 +885,75 @@ func (k *JPAKotlin) Generate(
 			}
 		}
 
+		// @Embedded
+		for i, embeddedField := range class.EmbeddedFields {
+			appendLine(indent + "@Embedded")
+			appendLine(indent + "@AttributeOverrides(")
+			overrides := make([]string, 0)
+			for _, column := range embeddedField.Columns {
+				overrides = append(overrides, indent+fmt.Sprintf("    AttributeOverride(name = \"%s\", column = Column(name = \"%s\"))",
+					NewKotlinField(column.EmbeddableColumn).Name, column.Name))
+			}
+			appendLine(strings.Join(overrides, ",\n"))
+			appendLine(indent + ")")
+
+			// embeddable class without default values can't be instantiated by no-arg constructor
+			line := fmt.Sprintf("var %s: %s = %s()", embeddedField.Name, embeddedField.ClassName, embeddedField.ClassName)
+			if embeddedField.Nullable() || !embeddedField.HasDefaultValues() {
+				line = fmt.Sprintf("var %s: %s? = null", embeddedField.Name, embeddedField.ClassName)
+			}
+			if len(class.Fields)+i < fieldCount-1 {
+				appendLine(indent + line + ",")
+				appendLine("")
+			} else {
+				appendLine(indent + line)
+			}
+		}
+
+		hasClassBody := len(class.OneToManyFields) > 0 || len(class.ManyToManyFields) > 0
+		classBody := ""
+		if hasClassBody {
+			classBody = " {"
+		}
 		if useDataClass {
 			if idEntityField != nil {
-				appendLine(fmt.Sprintf(") : %s<%s>", idEntityInterfaceName, idEntityField.Type))
+				appendLine(fmt.Sprintf(") : %s<%s>%s", idEntityInterfaceName, idEntityField.Type, classBody))
 			} else {
-				appendLine(")")
+				appendLine(")" + classBody)
 			}
 		} else {
 			appendLine("")
-			appendLine(fmt.Sprintf(") : AbstractJpaPersistable<%s>()", idClassName))
+			appendLine(fmt.Sprintf(") : AbstractJpaPersistable<%s>()%s", idClassName, classBody))
+		}
+
+		// @OneToMany
+		for _, oneToManyField := range class.OneToManyFields {
+			attributes := []string{fmt.Sprintf("mappedBy = \"%s\"", oneToManyField.MappedBy)}
+			if cascadeTypes != "" {
+				attributes = append(attributes, "cascade = "+cascadeTypes)
+			}
+			appendLine(fmt.Sprintf("    @OneToMany(%s)", strings.Join(attributes, ", ")))
+			appendLine(fmt.Sprintf("    var %s: MutableList<%s> = mutableListOf()", oneToManyField.Name, oneToManyField.Class.Name))
+			appendLine("")
+		}
+		// @ManyToMany
+		for _, manyToManyField := range class.ManyToManyFields {
+			if manyToManyField.MappedBy != "" {
+				appendLine(fmt.Sprintf("    @ManyToMany(mappedBy = \"%s\")", manyToManyField.MappedBy))
+			} else {
+				appendLine("    @ManyToMany")
+				appendLine("    @JoinTable(")
+				appendLine(fmt.Sprintf("        name = \"%s\",", manyToManyField.JoinTable))
+				appendLine(fmt.Sprintf("        joinColumns = [JoinColumn(name = \"%s\")],", manyToManyField.JoinColumn))
+				appendLine(fmt.Sprintf("        inverseJoinColumns = [JoinColumn(name = \"%s\")]", manyToManyField.InverseJoinColumn))
+				appendLine("    )")
+			}
+			appendLine(fmt.Sprintf("    var %s: MutableSet<%s> = mutableSetOf()", manyToManyField.Name, manyToManyField.Class.Name))
+			appendLine("")
+		}
+		if hasClassBody {
+			classLines = classLines[:len(classLines)-1]
+			appendLine("}")
 		}
 		appendLine("")
 
@@ -425,7 +965,10 @@ func (k *JPAKotlin) Generate(
 			javaImportSet.Add("java.io.Serializable")
 			addLine(fmt.Sprintf("data class %s(", idClassName))
 			for i, pkField := range class.PKFields {
-				line := indent + fmt.Sprintf("var %s: %s = %s", pkField.Name, pkField.Type, pkField.DefaultValue)
+				line := indent + fmt.Sprintf("var %s: %s", pkField.Name, pkField.Type)
+				if pkField.DefaultValue != "" {
+					line = line + " = " + pkField.DefaultValue
+				}
 				if i < pkFieldCount-1 {
 					line = line + ","
 				}
@@ -453,7 +996,9 @@ func (k *JPAKotlin) Generate(
 		log.Printf("[WRITE] %s", outputFile)
 
 		// Write Repos
-		if reposDir != "" {
+		if reposDir != "" && idClassName == "" {
+			log.Printf("Repository of %s is not generated. primary key is required", table.Name)
+		} else if reposDir != "" {
 			reposClassName := fmt.Sprintf("%sRepository", class.Name)
 			lines := []string{
 				"package " + reposPackage,
@@ -461,19 +1006,49 @@ func (k *JPAKotlin) Generate(
 				"import " + outputPackage + ".*",
 				"import org.springframework.data.jpa.repository.JpaRepository",
 				"import org.springframework.stereotype.Repository",
-				"",
-				"@Repository",
-				fmt.Sprintf("interface %s : JpaRepository<%s, %s>", reposClassName, class.Name, idClassName),
-				"",
 			}
+			finderLines, finderImports := k.repositoryFinderLines(class)
+			reposImportSet := NewStringSet(finderImports...)
+			if pkFieldCount == 1 {
+				reposImportSet.AddAll(class.PKFields[0].Imports)
+			}
+			for _, imp := range reposImportSet.Slice() {
+				lines = append(lines, "import "+imp)
+			}
+			lines = append(lines, "", "@Repository")
+			if len(finderLines) > 0 {
+				lines = append(lines, fmt.Sprintf("interface %s : JpaRepository<%s, %s> {", reposClassName, class.Name, idClassName))
+				lines = append(lines, finderLines...)
+				lines = append(lines, "}")
+			} else {
+				lines = append(lines, fmt.Sprintf("interface %s : JpaRepository<%s, %s>", reposClassName, class.Name, idClassName))
+			}
+			lines = append(lines, "")
 			if err := k.writeLines(path.Join(reposDir, reposClassName+".kt"), lines); err != nil {
 				return err
 			}
 		}
 	}
 
+	// write @Embeddable
+	embeddableNameSet := NewStringSet()
+	for _, class := range classes {
+		for _, embeddedField := range class.EmbeddedFields {
+			embeddableNameSet.Add(embeddedField.Embed.Embeddable)
+		}
+	}
+	for _, name := range embeddableNameSet.Slice() {
+		if err := k.generateEmbeddable(entityDir, outputPackage, persistence, schema.EmbeddableByName(name)); err != nil {
+			return err
+		}
+	}
+
 	// write graphql
-	if graphqlDir != "" {
+	if graphqlDir != "" && graphqlFramework != GraphqlFrameworkCoxautodev {
+		if err := k.generateGraphqlResolvers(graphqlDir, graphqlPackage, outputPackage, reposPackage, classes, graphqlFramework); err != nil {
+			return err
+		}
+	} else if graphqlDir != "" {
 		contents := []string{
 			"package " + graphqlPackage,
 			"",
@@ -512,6 +1087,64 @@ func (k *JPAKotlin) Generate(
 	return nil
 }
 
+// generateEmbeddable writes @Embeddable class. column names are overridden by @AttributeOverrides of entities.
+func (k *JPAKotlin) generateEmbeddable(outputDir string, packageName string, persistence string, embeddable *Embeddable) error {
+	indent := strings.Repeat(" ", 8)
+	className := strcase.ToCamel(embeddable.Name)
+
+	importSet := NewStringSet(persistence + ".persistence.*")
+	fieldLines := make([]string, 0)
+	for _, column := range embeddable.Columns {
+		field := NewKotlinField(column)
+		importSet.AddAll(field.Imports)
+
+		attributes := []string{fmt.Sprintf("name = \"%s\"", column.Name)}
+		if !column.Nullable {
+			attributes = append(attributes, "nullable = false")
+		}
+		if column.Type == ColTypeString && column.Size > 0 {
+			attributes = append(attributes, fmt.Sprintf("length = %d", column.Size))
+		}
+		if column.Type == ColTypeDouble || column.Type == ColTypeFloat || column.Type == ColTypeDecimal {
+			if column.Size > 0 {
+				attributes = append(attributes, fmt.Sprintf("precision = %d", column.Size))
+			}
+			if column.Scale > 0 {
+				attributes = append(attributes, fmt.Sprintf("scale = %d", column.Scale))
+			}
+		}
+
+		line := fmt.Sprintf("var %s: %s", field.Name, field.Type)
+		if field.DefaultValue != "" {
+			line = line + " = " + field.DefaultValue
+		} else if column.Nullable {
+			line = line + " = null"
+		}
+		fieldLines = append(fieldLines,
+			indent+fmt.Sprintf("@Column(%s)", strings.Join(attributes, ", ")),
+			indent+line+",",
+			"")
+	}
+	if len(fieldLines) > 0 {
+		fieldLines = fieldLines[:len(fieldLines)-1]
+		last := len(fieldLines) - 1
+		fieldLines[last] = strings.TrimSuffix(fieldLines[last], ",")
+	}
+
+	lines := make([]string, 0)
+	if packageName != "" {
+		lines = append(lines, "package "+packageName, "")
+	}
+	for _, imp := range importSet.Slice() {
+		lines = append(lines, "import "+imp)
+	}
+	lines = append(lines, "", "@Embeddable", fmt.Sprintf("data class %s(", className))
+	lines = append(lines, fieldLines...)
+	lines = append(lines, ")", "")
+
+	return k.writeLines(path.Join(outputDir, className+".kt"), lines)
+}
+
 func (k *JPAKotlin) writeLines(filename string, lines []string) error {
 	if err := ioutil.WriteFile(filename, []byte(strings.Join(lines, "\n")), 0644); err != nil {
 		return err
@@ -520,15 +1153,24 @@ func (k *JPAKotlin) writeLines(filename string, lines []string) error {
 	return nil
 }
 
-func (k *JPAKotlin) generateAbstractJpaPersistable(outputDir string, packageName string) error {
+func (k *JPAKotlin) generateAbstractJpaPersistable(outputDir string, packageName string, persistence string) error {
+	// ProxyUtils of spring data is used to unwrap proxy classes by default.
+	// Hibernate.getClass() is used with jakarta persistence(Hibernate 6) instead.
+	proxyImport := "org.springframework.data.util.ProxyUtils"
+	userClass := "ProxyUtils.getUserClass(other)"
+	if persistence == JpaPersistenceJakarta {
+		proxyImport = "org.hibernate.Hibernate"
+		userClass = "Hibernate.getClass(other)"
+	}
+
 	filename := path.Join(outputDir, "AbstractJpaPersistable.kt")
 	data := fmt.Sprintf(`package %s
 
-import org.springframework.data.util.ProxyUtils
+import %s
 import java.io.Serializable
-import javax.persistence.GeneratedValue
-import javax.persistence.Id
-import javax.persistence.MappedSuperclass
+import %s.persistence.GeneratedValue
+import %s.persistence.Id
+import %s.persistence.MappedSuperclass
 
 @MappedSuperclass
 abstract class AbstractJpaPersistable<T : Serializable> {
@@ -549,7 +1191,7 @@ abstract class AbstractJpaPersistable<T : Serializable> {
 
         if (this === other) return true
 
-        if (javaClass != ProxyUtils.getUserClass(other)) return false
+        if (javaClass != %s) return false
 
         other as AbstractJpaPersistable<*>
 
@@ -562,6 +1204,6 @@ abstract class AbstractJpaPersistable<T : Serializable> {
 
     override fun toString() = "Entity of type ${this.javaClass.name} with id: $id"
 }
-`, packageName)
+`, packageName, proxyImport, persistence, persistence, persistence, userClass)
 	return ioutil.WriteFile(filename, []byte(data), 0644)
 }
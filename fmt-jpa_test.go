@@ -0,0 +1,627 @@
+package main
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"strings"
+	"testing"
+)
+
+func newRelationTestSchema() *Schema {
+	return &Schema{
+		Tables: []*Table{
+			{
+				Name: "group",
+				Columns: []*Column{
+					{Name: "id", Type: ColTypeLong, PrimaryKey: true},
+				},
+			},
+			{
+				Name: "user",
+				Columns: []*Column{
+					{Name: "id", Type: ColTypeLong, PrimaryKey: true},
+					{Name: "group", Type: ColTypeString},
+					{Name: "group_id", Type: ColTypeLong, Ref: &Reference{Table: "group", Column: "id"}},
+					{Name: "sub_group_id", Type: ColTypeLong, Nullable: true, Ref: &Reference{Table: "group", Column: "id"}},
+					{Name: "parent_id", Type: ColTypeLong, Nullable: true, Ref: &Reference{Table: "user", Column: "id"}},
+				},
+			},
+		},
+	}
+}
+
+func TestJPAKotlin_resolveRelations(t *testing.T) {
+	schema := newRelationTestSchema()
+	output := &Output{Options: map[string]string{}}
+	classes := make([]*KotlinClass, 0)
+	for _, table := range schema.Tables {
+		classes = append(classes, NewKotlinClass(table, output, newAnnotationMapper(""), newPrefixMapper("")))
+	}
+
+	k := &JPAKotlin{}
+	if err := k.resolveRelations(classes, true, false); err != nil {
+		t.Fatal(err)
+	}
+
+	type field struct {
+		Name     string
+		Type     string
+		RefClass string
+	}
+	fields := make([]field, 0)
+	for _, f := range classes[1].Fields {
+		refClass := ""
+		if f.RefClass != nil {
+			refClass = f.RefClass.Name
+		}
+		fields = append(fields, field{Name: f.Name, Type: f.Type, RefClass: refClass})
+	}
+
+	// 'group' is used by column, so field names are derived from column names
+	expected := []field{
+		{Name: "id", Type: "Long"},
+		{Name: "group", Type: "String"},
+		{Name: "groupGroup", Type: "Group?", RefClass: "Group"},
+		{Name: "subGroup", Type: "Group?", RefClass: "Group"},
+		{Name: "user", Type: "User?", RefClass: "User"},
+	}
+	if diff := cmp.Diff(expected, fields); diff != "" {
+		t.Errorf("TestJPAKotlin_resolveRelations() mismatch (-expected +actual):\n%s", diff)
+	}
+
+	oneToManyNames := make([]string, 0)
+	for _, f := range classes[0].OneToManyFields {
+		oneToManyNames = append(oneToManyNames, f.Name+":"+f.MappedBy)
+	}
+	if diff := cmp.Diff([]string{"users:groupGroup", "usersBySubGroup:subGroup"}, oneToManyNames); diff != "" {
+		t.Errorf("TestJPAKotlin_resolveRelations() oneToMany mismatch (-expected +actual):\n%s", diff)
+	}
+}
+
+func TestJPAKotlin_resolveRelations_UnknownRelation(t *testing.T) {
+	table := &Table{
+		Name: "user",
+		Columns: []*Column{
+			{Name: "group_id", Type: ColTypeLong, Ref: &Reference{Table: "group", Column: "id"}},
+		},
+	}
+	output := &Output{Options: map[string]string{}}
+	newClasses := func() []*KotlinClass {
+		return []*KotlinClass{NewKotlinClass(table, output, newAnnotationMapper(""), newPrefixMapper(""))}
+	}
+
+	k := &JPAKotlin{}
+	if err := k.resolveRelations(newClasses(), false, false); err == nil {
+		t.Error("TestJPAKotlin_resolveRelations_UnknownRelation() error expected")
+	}
+
+	classes := newClasses()
+	if err := k.resolveRelations(classes, false, true); err != nil {
+		t.Fatal(err)
+	}
+	if field := classes[0].Fields[0]; field.RefClass != nil || field.Name != "groupId" {
+		t.Errorf("TestJPAKotlin_resolveRelations_UnknownRelation() unexpected field: %s", field.Name)
+	}
+}
+
+func TestJPAKotlin_Generate_ManyToOne(t *testing.T) {
+	schema := newRelationTestSchema()
+	schema.Tables[0].AddColumn(&Column{Name: "code", Type: ColTypeString, Size: 10, UniqueKey: true})
+	schema.Tables[1].AddColumn(&Column{Name: "main_group_code", Type: ColTypeString, Size: 10, Ref: &Reference{Table: "group", Column: "code"}})
+
+	files, err := generateOutput(t, schema, &Output{Format: FormatJpaKotlinData, Options: map[string]string{
+		FlagPackage:   "com.example.entity",
+		FlagRelation:  JpaRelationManyToOne,
+		FlagOneToMany: "true",
+		FlagFetch:     "eager",
+		FlagCascade:   "persist,merge",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user := files["com/example/entity/User.kt"]
+	assertContains(t, "User.kt", user,
+		"        @ManyToOne(fetch = FetchType.EAGER, optional = false)",
+		"        @JoinColumn(name = \"group_id\", nullable = false)",
+		"        var groupGroup: Group? = null,",
+		"",
+		"        @ManyToOne(fetch = FetchType.EAGER)",
+		"        @JoinColumn(name = \"sub_group_id\")",
+		"        var subGroup: Group? = null,",
+	)
+	// unique column other than primary key is referenced
+	assertContains(t, "User.kt", user,
+		"        @ManyToOne(fetch = FetchType.EAGER, optional = false)",
+		"        @JoinColumn(name = \"main_group_code\", referencedColumnName = \"code\", nullable = false)",
+		"        var mainGroupCode: Group? = null",
+	)
+
+	group := files["com/example/entity/Group.kt"]
+	assertContains(t, "Group.kt", group,
+		") {",
+		"    @OneToMany(mappedBy = \"groupGroup\", cascade = [CascadeType.PERSIST, CascadeType.MERGE])",
+		"    var users: MutableList<User> = mutableListOf()",
+		"",
+		"    @OneToMany(mappedBy = \"subGroup\", cascade = [CascadeType.PERSIST, CascadeType.MERGE])",
+		"    var usersBySubGroup: MutableList<User> = mutableListOf()",
+		"",
+		"    @OneToMany(mappedBy = \"mainGroupCode\", cascade = [CascadeType.PERSIST, CascadeType.MERGE])",
+		"    var usersByMainGroupCode: MutableList<User> = mutableListOf()",
+		"}",
+	)
+}
+
+func TestJPAKotlin_Generate_InvalidFetch(t *testing.T) {
+	_, err := generateOutput(t, newRelationTestSchema(), &Output{Format: FormatJpaKotlinData, Options: map[string]string{
+		FlagPackage:  "com.example.entity",
+		FlagRelation: JpaRelationManyToOne,
+		FlagFetch:    "eagerly",
+	}})
+	if err == nil || !strings.Contains(err.Error(), "unsupported fetch type: eagerly") {
+		t.Errorf("TestJPAKotlin_Generate_InvalidFetch() unexpected error: %v", err)
+	}
+}
+
+func newManyToManyTestSchema() *Schema {
+	return &Schema{
+		Tables: []*Table{
+			{
+				Name: "user",
+				Columns: []*Column{
+					{Name: "id", Type: ColTypeLong, PrimaryKey: true},
+					{Name: "roles", Type: ColTypeString},
+				},
+			},
+			{
+				Name: "role",
+				Columns: []*Column{
+					{Name: "id", Type: ColTypeLong, PrimaryKey: true},
+				},
+			},
+			{
+				Name: "user_role",
+				Columns: []*Column{
+					{Name: "user_id", Type: ColTypeLong, PrimaryKey: true, Ref: &Reference{Table: "user", Column: "id"}},
+					{Name: "role_id", Type: ColTypeLong, PrimaryKey: true, Ref: &Reference{Table: "role", Column: "id"}},
+				},
+			},
+			{
+				Name: "user_role_history",
+				Columns: []*Column{
+					{Name: "user_id", Type: ColTypeLong, PrimaryKey: true, Ref: &Reference{Table: "user", Column: "id"}},
+					{Name: "role_id", Type: ColTypeLong, PrimaryKey: true, Ref: &Reference{Table: "role", Column: "id"}},
+					{Name: "created_at", Type: ColTypeDateTime},
+				},
+			},
+		},
+	}
+}
+
+func TestJPAKotlin_resolveManyToMany(t *testing.T) {
+	schema := newManyToManyTestSchema()
+	output := &Output{Options: map[string]string{}}
+	classes := make([]*KotlinClass, 0)
+	for _, table := range schema.Tables {
+		classes = append(classes, NewKotlinClass(table, output, newAnnotationMapper(""), newPrefixMapper("")))
+	}
+
+	k := &JPAKotlin{}
+	result := k.resolveManyToMany(classes)
+
+	// pure join table is removed
+	classNames := make([]string, 0)
+	for _, class := range result {
+		classNames = append(classNames, class.Name)
+	}
+	if diff := cmp.Diff([]string{"User", "Role", "UserRoleHistory"}, classNames); diff != "" {
+		t.Errorf("TestJPAKotlin_resolveManyToMany() classes mismatch (-expected +actual):\n%s", diff)
+	}
+
+	type field struct {
+		Name              string
+		Class             string
+		MappedBy          string
+		JoinTable         string
+		JoinColumn        string
+		InverseJoinColumn string
+	}
+	toFields := func(class *KotlinClass) []field {
+		fields := make([]field, 0)
+		for _, f := range class.ManyToManyFields {
+			fields = append(fields, field{f.Name, f.Class.Name, f.MappedBy, f.JoinTable, f.JoinColumn, f.InverseJoinColumn})
+		}
+		return fields
+	}
+
+	// 'roles' is used by column of user
+	expectedUserFields := []field{
+		{Name: "rolesByRoleId", Class: "Role", JoinTable: "user_role", JoinColumn: "user_id", InverseJoinColumn: "role_id"},
+	}
+	if diff := cmp.Diff(expectedUserFields, toFields(classes[0])); diff != "" {
+		t.Errorf("TestJPAKotlin_resolveManyToMany() user mismatch (-expected +actual):\n%s", diff)
+	}
+	expectedRoleFields := []field{
+		{Name: "users", Class: "User", MappedBy: "rolesByRoleId"},
+	}
+	if diff := cmp.Diff(expectedRoleFields, toFields(classes[1])); diff != "" {
+		t.Errorf("TestJPAKotlin_resolveManyToMany() role mismatch (-expected +actual):\n%s", diff)
+	}
+}
+
+func TestJPAKotlin_Generate_ManyToMany(t *testing.T) {
+	files, err := generateOutput(t, newManyToManyTestSchema(), &Output{Format: FormatJpaKotlinData, Options: map[string]string{
+		FlagPackage:    "com.example.entity",
+		FlagManyToMany: "true",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := files["com/example/entity/UserRole.kt"]; ok {
+		t.Error("TestJPAKotlin_Generate_ManyToMany() join table entity is generated")
+	}
+	if _, ok := files["com/example/entity/UserRoleHistory.kt"]; !ok {
+		t.Error("TestJPAKotlin_Generate_ManyToMany() entity with extra columns is not generated")
+	}
+	assertContains(t, "User.kt", files["com/example/entity/User.kt"],
+		") {",
+		"    @ManyToMany",
+		"    @JoinTable(",
+		"        name = \"user_role\",",
+		"        joinColumns = [JoinColumn(name = \"user_id\")],",
+		"        inverseJoinColumns = [JoinColumn(name = \"role_id\")]",
+		"    )",
+		"    var rolesByRoleId: MutableSet<Role> = mutableSetOf()",
+		"}",
+	)
+	assertContains(t, "Role.kt", files["com/example/entity/Role.kt"],
+		") {",
+		"    @ManyToMany(mappedBy = \"rolesByRoleId\")",
+		"    var users: MutableSet<User> = mutableSetOf()",
+		"}",
+	)
+}
+
+func TestJPAKotlin_Generate_Jakarta(t *testing.T) {
+	schema := &Schema{
+		Tables: []*Table{
+			{
+				Name: "document",
+				Columns: []*Column{
+					{Name: "id", Type: "uuid", PrimaryKey: true},
+					{Name: "body", Type: "json", Nullable: true},
+				},
+			},
+		},
+	}
+	generate := func(persistence string) (map[string]string, error) {
+		return generateOutput(t, schema, &Output{Format: FormatJpaKotlin, Options: map[string]string{
+			FlagPackage:     "com.example",
+			FlagPersistence: persistence,
+		}})
+	}
+
+	files, err := generate(JpaPersistenceJakarta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	document := files["com/example/Document.kt"]
+	assertContains(t, "Document.kt", document,
+		"import org.hibernate.annotations.JdbcTypeCode",
+		"import org.hibernate.annotations.UuidGenerator",
+		"import org.hibernate.type.SqlTypes",
+		"import jakarta.persistence.*",
+		"import java.util.UUID",
+	)
+	assertContains(t, "Document.kt", document,
+		"        @Id",
+		"        @UuidGenerator",
+		"        @Column(nullable = false)",
+		"        var id: UUID,",
+		"",
+		"        @JdbcTypeCode(SqlTypes.JSON)",
+		"        var body: String?",
+		"",
+		") : AbstractJpaPersistable<UUID>()",
+	)
+	persistable := files["com/example/AbstractJpaPersistable.kt"]
+	assertContains(t, "AbstractJpaPersistable.kt", persistable, "import org.hibernate.Hibernate")
+	assertContains(t, "AbstractJpaPersistable.kt", persistable, "import jakarta.persistence.MappedSuperclass")
+	assertContains(t, "AbstractJpaPersistable.kt", persistable, "        if (javaClass != Hibernate.getClass(other)) return false")
+
+	// javax is used by default
+	files, err = generate("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertContains(t, "Document.kt", files["com/example/Document.kt"],
+		"        @Id",
+		"        @GeneratedValue",
+		"        @Column(nullable = false)",
+		"        var id: UUID,",
+	)
+	persistable = files["com/example/AbstractJpaPersistable.kt"]
+	assertContains(t, "AbstractJpaPersistable.kt", persistable, "import org.springframework.data.util.ProxyUtils")
+	assertContains(t, "AbstractJpaPersistable.kt", persistable, "        if (javaClass != ProxyUtils.getUserClass(other)) return false")
+
+	if _, err := generate("jakarta.ee"); err == nil {
+		t.Error("TestJPAKotlin_Generate_Jakarta() error expected for unsupported persistence")
+	}
+}
+
+func newRepositoryTestSchema() *Schema {
+	return &Schema{
+		Tables: []*Table{
+			{
+				Name: "group",
+				Columns: []*Column{
+					{Name: "id", Type: ColTypeLong, PrimaryKey: true},
+				},
+			},
+			{
+				Name: "user",
+				Columns: []*Column{
+					{Name: "id", Type: ColTypeLong, PrimaryKey: true, UniqueKey: true},
+					{Name: "email", Type: ColTypeString, UniqueKey: true},
+					{Name: "group_id", Type: ColTypeLong, Nullable: true, Ref: &Reference{Table: "group", Column: "id"}},
+					{Name: "name", Type: ColTypeString},
+					{Name: "created_at", Type: ColTypeDateTime},
+				},
+				Indices: []*Index{
+					{Name: "idx_group", Columns: []string{"group_id"}},
+					{Name: "idx_name", Columns: []string{"name", "created_at"}},
+					{Name: "idx_unknown", Columns: []string{"deleted_at"}},
+				},
+			},
+		},
+	}
+}
+
+func TestNewRepositoryFinders(t *testing.T) {
+	schema := newRepositoryTestSchema()
+
+	type finder struct {
+		Unique  bool
+		Columns string
+	}
+	finders := make([]finder, 0)
+	for _, f := range newRepositoryFinders(schema.Tables[1]) {
+		names := make([]string, 0)
+		for _, column := range f.columns {
+			names = append(names, column.Name)
+		}
+		finders = append(finders, finder{Unique: f.unique, Columns: strings.Join(names, ",")})
+	}
+
+	// primary key, duplicated column sets and indices of unknown columns are skipped
+	expected := []finder{
+		{Unique: true, Columns: "email"},
+		{Columns: "group_id"},
+		{Columns: "name,created_at"},
+	}
+	if diff := cmp.Diff(expected, finders); diff != "" {
+		t.Errorf("TestNewRepositoryFinders() mismatch (-expected +actual):\n%s", diff)
+	}
+
+	if finders := newRepositoryFinders(schema.Tables[0]); len(finders) != 0 {
+		t.Errorf("TestNewRepositoryFinders() expected no finders, got %d", len(finders))
+	}
+}
+
+func TestJPAKotlin_Generate_Repository(t *testing.T) {
+	files, err := generateOutput(t, newRepositoryTestSchema(), &Output{Format: FormatJpaKotlinData, Options: map[string]string{
+		FlagPackage:      "com.example.entity",
+		FlagReposPackage: "com.example.repos",
+		FlagRelation:     JpaRelationManyToOne,
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// @ManyToOne field is queried by property of referenced class
+	expected := strings.Join([]string{
+		"package com.example.repos",
+		"",
+		"import com.example.entity.*",
+		"import org.springframework.data.jpa.repository.JpaRepository",
+		"import org.springframework.stereotype.Repository",
+		"import java.sql.Timestamp",
+		"",
+		"@Repository",
+		"interface UserRepository : JpaRepository<User, Long> {",
+		"    fun findByEmail(email: String): User?",
+		"",
+		"    fun existsByEmail(email: String): Boolean",
+		"",
+		"    fun findAllByGroupId(groupId: Long): List<User>",
+		"",
+		"    fun findAllByNameAndCreatedAt(name: String, createdAt: Timestamp): List<User>",
+		"}",
+		"",
+	}, "\n")
+	if diff := cmp.Diff(expected, files["com/example/repos/UserRepository.kt"]); diff != "" {
+		t.Errorf("TestJPAKotlin_Generate_Repository() mismatch (-expected +actual):\n%s", diff)
+	}
+
+	// repository body is omitted without finders
+	assertContains(t, "GroupRepository.kt", files["com/example/repos/GroupRepository.kt"],
+		"@Repository",
+		"interface GroupRepository : JpaRepository<Group, Long>",
+		"",
+	)
+}
+
+func TestJPAKotlin_Generate_Target(t *testing.T) {
+	schema := &Schema{
+		Tables: []*Table{
+			{
+				Name: "user",
+				Columns: []*Column{
+					{Name: "id", Type: ColTypeLong, PrimaryKey: true, AutoIncremental: true},
+					{
+						Name:     "role",
+						Type:     ColTypeString,
+						Size:     20,
+						Nullable: true,
+						Targets: map[string]*Target{
+							FormatJpaKotlin: {
+								Name:        "userRole",
+								Type:        "UserRole",
+								Annotations: []string{"@Enumerated(EnumType.STRING)"},
+								Imports:     []string{"com.example.type.UserRole"},
+							},
+						},
+					},
+				},
+				Targets: map[string]*Target{
+					FormatJpaKotlin: {
+						Name:        "Member",
+						Annotations: []string{"@DynamicUpdate"},
+						Imports:     []string{"org.hibernate.annotations.DynamicUpdate"},
+					},
+				},
+			},
+		},
+	}
+
+	files, err := generateOutput(t, schema, &Output{Format: FormatJpaKotlinData, Options: map[string]string{
+		FlagPackage: "com.example.entity",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := strings.Join([]string{
+		"package com.example.entity",
+		"",
+		"import com.example.type.UserRole",
+		"import org.hibernate.annotations.DynamicUpdate",
+		"import javax.persistence.*",
+		"",
+		"",
+		"@DynamicUpdate",
+		"@Entity",
+		"@Table(name = \"user\")",
+		"data class Member(",
+		"        @Id",
+		"        @GeneratedValue(strategy = GenerationType.AUTO)",
+		"        @Column(nullable = false)",
+		"        var id: Long = 0L,",
+		"",
+		"        @Enumerated(EnumType.STRING)",
+		"        @Column(name = \"role\", length = 20)",
+		"        var userRole: UserRole?",
+		")",
+		"",
+	}, "\n")
+	if diff := cmp.Diff(expected, files["com/example/entity/Member.kt"]); diff != "" {
+		t.Errorf("TestJPAKotlin_Generate_Target() mismatch (-expected +actual):\n%s", diff)
+	}
+}
+
+func TestJPAKotlin_Generate_Embeds(t *testing.T) {
+	schema := newEmbedTestSchema()
+	schema.Normalize()
+
+	files, err := generateOutput(t, schema, &Output{Format: FormatJpaKotlinData, Options: map[string]string{
+		FlagPackage: "com.example.entity",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"com/example/entity/User.kt": strings.Join([]string{
+			"package com.example.entity",
+			"",
+			"import javax.persistence.*",
+			"",
+			"",
+			"@Entity",
+			"@Table(name = \"user\")",
+			"data class User(",
+			"        @Id",
+			"        @Column(nullable = false)",
+			"        var id: Long = 0L,",
+			"",
+			"        @Embedded",
+			"        @AttributeOverrides(",
+			"            AttributeOverride(name = \"city\", column = Column(name = \"home_city\")),",
+			"            AttributeOverride(name = \"zipCode\", column = Column(name = \"home_zip_code\"))",
+			"        )",
+			"        var home: Address = Address(),",
+			"",
+			"        @Embedded",
+			"        @AttributeOverrides(",
+			"            AttributeOverride(name = \"city\", column = Column(name = \"work_city\")),",
+			"            AttributeOverride(name = \"zipCode\", column = Column(name = \"work_zip_code\"))",
+			"        )",
+			"        var work: Address = Address()",
+			")",
+			"",
+		}, "\n"),
+		"com/example/entity/Address.kt": strings.Join([]string{
+			"package com.example.entity",
+			"",
+			"import javax.persistence.*",
+			"",
+			"@Embeddable",
+			"data class Address(",
+			"        @Column(name = \"city\", nullable = false, length = 40)",
+			"        var city: String = \"\",",
+			"",
+			"        @Column(name = \"zip_code\", length = 10)",
+			"        var zipCode: String? = null",
+			")",
+			"",
+		}, "\n"),
+	}
+	if diff := cmp.Diff(expected, files); diff != "" {
+		t.Errorf("TestJPAKotlin_Generate_Embeds() mismatch (-expected +actual):\n%s", diff)
+	}
+}
+
+func TestJPAKotlin_Generate_EmbedsWithoutDefaultValues(t *testing.T) {
+	schema := newEmbedTestSchema()
+	schema.Embeddables = append(schema.Embeddables,
+		&Embeddable{
+			Name: "token",
+			Columns: []*Column{
+				{Name: "uid", Type: "uuid"},
+			},
+		},
+		&Embeddable{
+			Name: "money",
+			Columns: []*Column{
+				{Name: "amount", Type: ColTypeDecimal, Size: 10, Scale: 2},
+				{
+					Name: "currency",
+					Type: ColTypeString,
+					Size: 3,
+					Targets: map[string]*Target{
+						FormatJpaKotlin: {Type: "Currency", Imports: []string{"java.util.Currency"}},
+					},
+				},
+			},
+		},
+	)
+	schema.Tables[0].Embeds = []*Embed{
+		{Embeddable: "address", Prefix: "home_"},
+		{Embeddable: "token", Prefix: "access_"},
+		{Embeddable: "money", Prefix: "balance_"},
+	}
+	schema.Normalize()
+
+	files, err := generateOutput(t, schema, &Output{Format: FormatJpaKotlinData, Options: map[string]string{
+		FlagPackage: "com.example.entity",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// embeddable classes with non-null fields without default values are nullable
+	user := files["com/example/entity/User.kt"]
+	assertContains(t, "User.kt", user, "        var home: Address = Address(),")
+	assertContains(t, "User.kt", user, "        var access: Token? = null,")
+	assertContains(t, "User.kt", user, "        var balance: Money? = null")
+}
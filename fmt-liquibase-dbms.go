@@ -0,0 +1,183 @@
+package main
+
+import (
+	"log"
+	"strings"
+)
+
+const lqMysqlTableOptions = "ENGINE=InnoDB DEFAULT CHARSET=utf8mb4"
+
+// changes which are not supported by the target dbms.
+var lqUnsupportedChangeSetByDbms = map[string]*StringSet{
+	"sqlite": NewStringSet(
+		"addAutoIncrement",
+		"dropDefaultValue",
+		"addForeignKeyConstraint",
+		"dropForeignKeyConstraint",
+		"setTableRemarks",
+		"setColumnRemarks",
+	),
+	"derby": NewStringSet(
+		"setTableRemarks",
+		"setColumnRemarks",
+	),
+}
+
+type LqModifySql struct {
+	Dbms   string             `yaml:"dbms,omitempty"`
+	Append *LqModifySqlAppend `yaml:"append,omitempty"`
+}
+
+type LqModifySqlAppend struct {
+	Value string `yaml:"value"`
+}
+
+type LqTableExists struct {
+	TableName string `yaml:"tableName"`
+}
+
+type LqColumnExists struct {
+	TableName  string `yaml:"tableName"`
+	ColumnName string `yaml:"columnName"`
+}
+
+// applyLqDbms makes changelog target the given dbms.
+//   - changes unsupported by the dbms are removed.
+//   - tableExists/columnExists preconditions are added, so changelog can be applied to partially migrated databases.
+//   - table options are appended to createTable on MySQL.
+func applyLqDbms(changeLog *LqYaml, dbms string, tableOptions string) {
+	dbms = strings.ToLower(strings.TrimSpace(dbms))
+	if dbms == "" {
+		return
+	}
+	isMysql := dbms == DbmsMysql || dbms == "mariadb"
+	if isMysql && tableOptions == "" {
+		tableOptions = lqMysqlTableOptions
+	}
+	unsupportedSet := lqUnsupportedChangeSetByDbms[dbms]
+
+	entries := make([]interface{}, 0, len(changeLog.DatabaseChangeLog))
+	for _, entry := range changeLog.DatabaseChangeLog {
+		entryMap, ok := entry.(map[string]interface{})
+		if !ok {
+			entries = append(entries, entry)
+			continue
+		}
+		changeSet, ok := entryMap["changeSet"].(*LqChangeSet)
+		if !ok {
+			entries = append(entries, entry)
+			continue
+		}
+
+		// changeSet for the other dbms
+		if dbmsCondition, ok := changeSet.PreConditions["dbms"].(map[string]string); ok {
+			if !NewStringSet(strings.Split(strings.ReplaceAll(dbmsCondition["type"], " ", ""), ",")...).Contains(dbms) {
+				log.Printf("changeSet %s is skipped. not for %s", changeSet.Id, dbms)
+				continue
+			}
+			changeSet.PreConditions = make(map[string]interface{})
+		}
+
+		if unsupportedSet != nil {
+			changeSet.Changes = filterLqChanges(changeSet.Changes, unsupportedSet, changeSet.Id, dbms)
+			changeSet.Rollback = filterLqChanges(changeSet.Rollback, unsupportedSet, changeSet.Id, dbms)
+			if len(changeSet.Changes) == 0 {
+				continue
+			}
+		}
+
+		addLqExistsPreConditions(changeSet)
+
+		if isMysql && tableOptions != "" {
+			for _, change := range changeSet.Changes {
+				if _, ok := change["createTable"]; ok {
+					changeSet.ModifySql = append(changeSet.ModifySql, &LqModifySql{
+						Dbms:   dbms,
+						Append: &LqModifySqlAppend{Value: " " + tableOptions},
+					})
+					break
+				}
+			}
+		}
+		entries = append(entries, entry)
+	}
+	changeLog.DatabaseChangeLog = entries
+}
+
+func filterLqChanges(changes []map[string]interface{}, unsupportedSet *StringSet, changeSetId string, dbms string) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0, len(changes))
+	for _, change := range changes {
+		supported := true
+		for key := range change {
+			if unsupportedSet.Contains(key) {
+				log.Printf("%s is not supported by %s. changeSet: %s", key, dbms, changeSetId)
+				supported = false
+			}
+		}
+		if supported {
+			result = append(result, change)
+		}
+	}
+	return result
+}
+
+// addLqExistsPreConditions adds preconditions which mark changeSet as ran if it's already applied.
+// conditions of all changes are combined with 'and' if there are multiple conditions.
+func addLqExistsPreConditions(changeSet *LqChangeSet) {
+	conditionList := make([]map[string]interface{}, 0)
+	for _, change := range changeSet.Changes {
+		for _, value := range change {
+			if condition := lqExistsCondition(value); condition != nil {
+				conditionList = append(conditionList, condition)
+			}
+		}
+	}
+
+	var conditions map[string]interface{}
+	switch len(conditionList) {
+	case 0:
+		return
+	case 1:
+		conditions = conditionList[0]
+	default:
+		conditions = map[string]interface{}{"and": conditionList}
+	}
+
+	if changeSet.PreConditions == nil {
+		changeSet.PreConditions = make(map[string]interface{})
+	}
+	changeSet.PreConditions["onFail"] = "MARK_RAN"
+	for key, value := range conditions {
+		changeSet.PreConditions[key] = value
+	}
+}
+
+// lqExistsCondition returns precondition which is true if the change is not applied yet.
+// returns nil if the change has no precondition.
+func lqExistsCondition(change interface{}) map[string]interface{} {
+	switch c := change.(type) {
+	case *LqCreateTable:
+		return map[string]interface{}{
+			"not": []map[string]interface{}{
+				{"tableExists": &LqTableExists{TableName: c.TableName}},
+			},
+		}
+	case *LqDropTable:
+		return map[string]interface{}{"tableExists": &LqTableExists{TableName: c.TableName}}
+	case *LqRenameTable:
+		return map[string]interface{}{"tableExists": &LqTableExists{TableName: c.OldTableName}}
+	case *LqAddColumn:
+		notConditions := make([]map[string]interface{}, 0)
+		for _, column := range c.Columns {
+			notConditions = append(notConditions, map[string]interface{}{
+				"columnExists": &LqColumnExists{TableName: c.TableName, ColumnName: column["column"].Name},
+			})
+		}
+		return map[string]interface{}{"not": notConditions}
+	case *LqDropColumn:
+		return map[string]interface{}{"columnExists": &LqColumnExists{TableName: c.TableName, ColumnName: c.ColumnName}}
+	case *LqRenameColumn:
+		return map[string]interface{}{"columnExists": &LqColumnExists{TableName: c.TableName, ColumnName: c.OldColumnName}}
+	}
+	return nil
+}
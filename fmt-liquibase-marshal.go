@@ -0,0 +1,257 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"gopkg.in/yaml.v2"
+	"strings"
+)
+
+const (
+	LqFormatYaml = "yaml"
+	LqFormatXml  = "xml"
+	LqFormatJson = "json"
+
+	lqXmlNamespace      = "http://www.liquibase.org/xml/ns/dbchangelog"
+	lqXmlSchemaLocation = "http://www.liquibase.org/xml/ns/dbchangelog http://www.liquibase.org/xml/ns/dbchangelog/dbchangelog-3.8.xsd"
+)
+
+// list keys which do not have a corresponding XML element.
+// items are written as child elements of the parent.
+var lqXmlFlattenKeySet = NewStringSet("changes", "columns")
+
+// list keys whose items are written as repeated elements with the key name.
+var lqXmlRepeatKeySet = NewStringSet("modifySql")
+
+// getLqFormat returns changelog format from file extension.
+func getLqFormat(filename string) string {
+	switch {
+	case strings.HasSuffix(filename, ".xml"):
+		return LqFormatXml
+	case strings.HasSuffix(filename, ".json"):
+		return LqFormatJson
+	case strings.HasSuffix(filename, ".yaml"), strings.HasSuffix(filename, ".yml"):
+		return LqFormatYaml
+	case strings.HasSuffix(filename, ".sql"):
+		return LqFormatSql
+	default:
+		return ""
+	}
+}
+
+// marshalLqChangeLog serializes changelog in the given format.
+// XML and JSON are converted from the YAML representation to keep the content identical.
+func marshalLqChangeLog(changeLog *LqYaml, format string) ([]byte, error) {
+	data, err := yaml.Marshal(changeLog)
+	if err != nil {
+		return nil, err
+	}
+
+	switch format {
+	case "", LqFormatYaml:
+		return data, nil
+	case LqFormatXml, LqFormatJson:
+		var mapSlice yaml.MapSlice
+		if err := yaml.Unmarshal(data, &mapSlice); err != nil {
+			return nil, err
+		}
+		buf := &bytes.Buffer{}
+		if format == LqFormatXml {
+			err = writeLqXml(buf, mapSlice)
+		} else {
+			err = writeLqJson(buf, mapSlice, "")
+			buf.WriteString("\n")
+		}
+		if err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	default:
+		return nil, fmt.Errorf("unsupported changelog format: %s", format)
+	}
+}
+
+// writeLqJson writes YAML value as JSON, preserving key order.
+func writeLqJson(buf *bytes.Buffer, value interface{}, indent string) error {
+	childIndent := indent + "  "
+
+	switch v := value.(type) {
+	case yaml.MapSlice:
+		if len(v) == 0 {
+			buf.WriteString("{}")
+			return nil
+		}
+		buf.WriteString("{\n")
+		for i, item := range v {
+			buf.WriteString(childIndent)
+			if err := writeLqJson(buf, fmt.Sprintf("%v", item.Key), childIndent); err != nil {
+				return err
+			}
+			buf.WriteString(": ")
+			if err := writeLqJson(buf, item.Value, childIndent); err != nil {
+				return err
+			}
+			if i < len(v)-1 {
+				buf.WriteString(",")
+			}
+			buf.WriteString("\n")
+		}
+		buf.WriteString(indent + "}")
+	case []interface{}:
+		if len(v) == 0 {
+			buf.WriteString("[]")
+			return nil
+		}
+		buf.WriteString("[\n")
+		for i, item := range v {
+			buf.WriteString(childIndent)
+			if err := writeLqJson(buf, item, childIndent); err != nil {
+				return err
+			}
+			if i < len(v)-1 {
+				buf.WriteString(",")
+			}
+			buf.WriteString("\n")
+		}
+		buf.WriteString(indent + "]")
+	default:
+		data := &bytes.Buffer{}
+		encoder := json.NewEncoder(data)
+		encoder.SetEscapeHTML(false)
+		if err := encoder.Encode(v); err != nil {
+			return err
+		}
+		buf.Write(bytes.TrimRight(data.Bytes(), "\n"))
+	}
+	return nil
+}
+
+// writeLqXml writes changelog as liquibase XML.
+// scalar values are written as attributes, maps and lists as child elements.
+func writeLqXml(buf *bytes.Buffer, changeLog yaml.MapSlice) error {
+	buf.WriteString(xml.Header)
+
+	rootAttrs := []string{
+		fmt.Sprintf(`xmlns="%s"`, lqXmlNamespace),
+		`xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance"`,
+		fmt.Sprintf(`xsi:schemaLocation="%s"`, lqXmlSchemaLocation),
+	}
+
+	entries := make([]interface{}, 0)
+	for _, item := range changeLog {
+		if item.Key == "databaseChangeLog" {
+			if list, ok := item.Value.([]interface{}); ok {
+				entries = list
+			}
+		}
+	}
+
+	// changelog level properties such as objectQuotingStrategy are attributes of root element
+	children := make([]yaml.MapItem, 0)
+	for _, entry := range entries {
+		for _, item := range toMapSlice(entry) {
+			if isLqScalar(item.Value) {
+				rootAttrs = append(rootAttrs, lqXmlAttr(item.Key, item.Value))
+			} else {
+				children = append(children, item)
+			}
+		}
+	}
+
+	buf.WriteString(fmt.Sprintf("<databaseChangeLog %s>\n", strings.Join(rootAttrs, "\n        ")))
+	for _, child := range children {
+		if err := writeLqXmlElement(buf, fmt.Sprintf("%v", child.Key), child.Value, "  "); err != nil {
+			return err
+		}
+	}
+	buf.WriteString("</databaseChangeLog>\n")
+	return nil
+}
+
+func writeLqXmlElement(buf *bytes.Buffer, name string, value interface{}, indent string) error {
+	attrs := make([]string, 0)
+	children := make([]yaml.MapItem, 0)
+
+	switch v := value.(type) {
+	case yaml.MapSlice:
+		for _, item := range v {
+			key := fmt.Sprintf("%v", item.Key)
+			switch {
+			case isLqScalar(item.Value):
+				attrs = append(attrs, lqXmlAttr(key, item.Value))
+			case lqXmlFlattenKeySet.Contains(key):
+				for _, listItem := range toList(item.Value) {
+					children = append(children, toMapSlice(listItem)...)
+				}
+			case lqXmlRepeatKeySet.Contains(key):
+				for _, listItem := range toList(item.Value) {
+					children = append(children, yaml.MapItem{Key: key, Value: listItem})
+				}
+			default:
+				children = append(children, item)
+			}
+		}
+	case []interface{}:
+		// list is written as wrapper element. e.g. rollback
+		for _, listItem := range v {
+			children = append(children, toMapSlice(listItem)...)
+		}
+	default:
+		// scalar value is written as element text. e.g. sql
+		buf.WriteString(fmt.Sprintf("%s<%s>", indent, name))
+		if err := xml.EscapeText(buf, []byte(fmt.Sprintf("%v", v))); err != nil {
+			return err
+		}
+		buf.WriteString(fmt.Sprintf("</%s>\n", name))
+		return nil
+	}
+
+	startTag := name
+	if len(attrs) > 0 {
+		startTag += " " + strings.Join(attrs, " ")
+	}
+	if len(children) == 0 {
+		buf.WriteString(fmt.Sprintf("%s<%s/>\n", indent, startTag))
+		return nil
+	}
+
+	buf.WriteString(fmt.Sprintf("%s<%s>\n", indent, startTag))
+	for _, child := range children {
+		if err := writeLqXmlElement(buf, fmt.Sprintf("%v", child.Key), child.Value, indent+"  "); err != nil {
+			return err
+		}
+	}
+	buf.WriteString(fmt.Sprintf("%s</%s>\n", indent, name))
+	return nil
+}
+
+func lqXmlAttr(key interface{}, value interface{}) string {
+	buf := &bytes.Buffer{}
+	_ = xml.EscapeText(buf, []byte(fmt.Sprintf("%v", value)))
+	return fmt.Sprintf(`%v="%s"`, key, buf.String())
+}
+
+func isLqScalar(value interface{}) bool {
+	switch value.(type) {
+	case yaml.MapSlice, []interface{}:
+		return false
+	default:
+		return true
+	}
+}
+
+func toMapSlice(value interface{}) yaml.MapSlice {
+	if mapSlice, ok := value.(yaml.MapSlice); ok {
+		return mapSlice
+	}
+	return yaml.MapSlice{}
+}
+
+func toList(value interface{}) []interface{} {
+	if list, ok := value.([]interface{}); ok {
+		return list
+	}
+	return []interface{}{}
+}
@@ -0,0 +1,265 @@
+package main
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v2"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+const (
+	LqSplitTable = "table"
+	LqSplitGroup = "group"
+
+	lqMasterChangeLogFilename = "db.changelog-master.yaml"
+	lqDefaultGroupName        = "default"
+	lqDropForeignKeysUnitName = "drop-foreign-keys"
+	lqAddForeignKeysUnitName  = "foreign-keys"
+)
+
+// lqChangeLogUnit is a changelog split from the whole changelog.
+type lqChangeLogUnit struct {
+	name       string
+	tableNames []string
+	changeLog  *LqYaml
+}
+
+// splitLqChangeLog splits changelog per table or group.
+// foreign keys are dropped in the first unit and added in the last unit,
+// since tables of different units can reference each other.
+// other units are sorted in foreign key dependency order, so referenced tables are created first.
+// group name is set as labels and context of changeSets.
+func splitLqChangeLog(changeLog *LqYaml, split string, schema *Schema, oldSchema *Schema) ([]*lqChangeLogUnit, error) {
+	if split != LqSplitTable && split != LqSplitGroup {
+		return nil, fmt.Errorf("unsupported split: %s", split)
+	}
+
+	// find table from both schemas. removed tables only exist in old schema.
+	tableByName := schema.TableByName()
+	if oldSchema != nil {
+		for name, table := range oldSchema.TableByName() {
+			if _, ok := tableByName[name]; !ok {
+				tableByName[name] = table
+			}
+		}
+	}
+	unitNameOf := func(tableName string) string {
+		if split == LqSplitTable {
+			return tableName
+		}
+		if table := tableByName[tableName]; table != nil && table.Group != "" {
+			return table.Group
+		}
+		return lqDefaultGroupName
+	}
+
+	newUnit := func(name string) *lqChangeLogUnit {
+		return &lqChangeLogUnit{
+			name:       name,
+			tableNames: make([]string, 0),
+			changeLog:  &LqYaml{DatabaseChangeLog: make([]interface{}, 0)},
+		}
+	}
+	dropForeignKeysUnit := newUnit(lqDropForeignKeysUnitName)
+	addForeignKeysUnit := newUnit(lqAddForeignKeysUnitName)
+
+	// changelog properties are copied to every unit
+	properties := make([]interface{}, 0)
+	units := make([]*lqChangeLogUnit, 0)
+	unitByName := make(map[string]*lqChangeLogUnit)
+	for _, entry := range changeLog.DatabaseChangeLog {
+		entryMap, ok := entry.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		changeSet, ok := entryMap["changeSet"].(*LqChangeSet)
+		if !ok {
+			properties = append(properties, entry)
+			continue
+		}
+
+		if table := tableByName[changeSet.tableName]; table != nil && table.Group != "" {
+			changeSet.Labels = table.Group
+			changeSet.Context = table.Group
+		}
+
+		var unit *lqChangeLogUnit
+		switch lqChangeSetKey(changeSet) {
+		case "dropForeignKeyConstraint":
+			unit = dropForeignKeysUnit
+		case "addForeignKeyConstraint":
+			unit = addForeignKeysUnit
+		default:
+			name := unitNameOf(changeSet.tableName)
+			unit, ok = unitByName[name]
+			if !ok {
+				unit = newUnit(name)
+				units = append(units, unit)
+				unitByName[name] = unit
+			}
+		}
+		if !NewStringSet(unit.tableNames...).Contains(changeSet.tableName) {
+			unit.tableNames = append(unit.tableNames, changeSet.tableName)
+		}
+		unit.changeLog.AddChangeSet(changeSet)
+	}
+	for _, unit := range append(units, dropForeignKeysUnit, addForeignKeysUnit) {
+		entries := append(make([]interface{}, 0), properties...)
+		unit.changeLog.DatabaseChangeLog = append(entries, unit.changeLog.DatabaseChangeLog...)
+	}
+
+	// find units referenced by foreign keys
+	dependencies := make(map[string]*StringSet)
+	for _, unit := range units {
+		dependencySet := NewStringSet()
+		for _, tableName := range unit.tableNames {
+			table := tableByName[tableName]
+			if table == nil {
+				continue
+			}
+			for _, column := range table.Columns {
+				if column.Ref == nil {
+					continue
+				}
+				if refName := unitNameOf(column.Ref.Table); refName != unit.name {
+					dependencySet.Add(refName)
+				}
+			}
+		}
+		dependencies[unit.name] = dependencySet
+	}
+
+	// sort units. original order is kept if there's no dependency or dependencies are circular.
+	result := make([]*lqChangeLogUnit, 0, len(units)+2)
+	if len(dropForeignKeysUnit.tableNames) > 0 {
+		result = append(result, dropForeignKeysUnit)
+	}
+	sortedCount := len(result)
+	sortedNameSet := NewStringSet()
+	for len(result)-sortedCount < len(units) {
+		var next *lqChangeLogUnit
+		for _, unit := range units {
+			if sortedNameSet.Contains(unit.name) {
+				continue
+			}
+			if next == nil {
+				next = unit
+			}
+			ready := true
+			for _, dependency := range dependencies[unit.name].Slice() {
+				if _, ok := unitByName[dependency]; ok && !sortedNameSet.Contains(dependency) {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				next = unit
+				break
+			}
+		}
+		result = append(result, next)
+		sortedNameSet.Add(next.name)
+	}
+	if len(addForeignKeysUnit.tableNames) > 0 {
+		result = append(result, addForeignKeysUnit)
+	}
+	return result, nil
+}
+
+// lqChangeSetKey returns the change key if all changes of the changeSet have the same key.
+func lqChangeSetKey(changeSet *LqChangeSet) string {
+	result := ""
+	for _, change := range changeSet.Changes {
+		for key := range change {
+			if result != "" && result != key {
+				return ""
+			}
+			result = key
+		}
+	}
+	return result
+}
+
+// writeLqMasterChangeLog writes master changelog which includes changelog files in order.
+func writeLqMasterChangeLog(masterFile string, changeLogFiles []string) error {
+	return updateLqMasterChangeLog(masterFile, yaml.MapSlice{}, changeLogFiles)
+}
+
+// appendLqIncludes adds 'include' entries of changeLogFiles to the master changelog.
+// master changelog is created if not exists. existing entries are kept as they are.
+func appendLqIncludes(masterFile string, changeLogFiles []string) error {
+	master := yaml.MapSlice{}
+	if data, err := ioutil.ReadFile(masterFile); err == nil {
+		if err := yaml.Unmarshal(data, &master); err != nil {
+			return err
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	return updateLqMasterChangeLog(masterFile, master, changeLogFiles)
+}
+
+func updateLqMasterChangeLog(masterFile string, master yaml.MapSlice, changeLogFiles []string) error {
+	entries := make([]interface{}, 0)
+	index := -1
+	for i, item := range master {
+		if item.Key == "databaseChangeLog" {
+			entries = toList(item.Value)
+			index = i
+		}
+	}
+
+	// included files
+	includedSet := NewStringSet()
+	for _, entry := range entries {
+		for _, item := range toMapSlice(entry) {
+			if item.Key != "include" {
+				continue
+			}
+			for _, attr := range toMapSlice(item.Value) {
+				if attr.Key == "file" {
+					includedSet.Add(fmt.Sprintf("%v", attr.Value))
+				}
+			}
+		}
+	}
+
+	for _, changeLogFile := range changeLogFiles {
+		relPath, err := filepath.Rel(filepath.Dir(masterFile), changeLogFile)
+		if err != nil {
+			return err
+		}
+		relPath = filepath.ToSlash(relPath)
+
+		// skip if already included
+		if includedSet.Contains(relPath) {
+			log.Printf("%s is already included in %s", relPath, masterFile)
+			continue
+		}
+		includedSet.Add(relPath)
+
+		entries = append(entries, yaml.MapSlice{
+			{Key: "include", Value: yaml.MapSlice{
+				{Key: "file", Value: relPath},
+				{Key: "relativeToChangelogFile", Value: true},
+			}},
+		})
+	}
+	if index < 0 {
+		master = append(master, yaml.MapItem{Key: "databaseChangeLog", Value: entries})
+	} else {
+		master[index].Value = entries
+	}
+
+	data, err := yaml.Marshal(master)
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(masterFile, data, 0644); err != nil {
+		return err
+	}
+	log.Printf("[WRITE] %s", masterFile)
+	return nil
+}
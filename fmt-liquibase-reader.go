@@ -0,0 +1,631 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v2"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FromFile reads liquibase YAML changelog and replays changes in order.
+// 'include' and 'includeAll' entries are read recursively.
+func (l *Liquibase) FromFile(filename string) error {
+	l.schema = nil
+
+	reader := &lqChangeLogReader{
+		builder:  newSchemaBuilder(),
+		rootDir:  filepath.Dir(filename),
+		visitSet: NewStringSet(),
+	}
+	if err := reader.readFile(filename); err != nil {
+		return err
+	}
+
+	schema := reader.builder.schema()
+	schema.Author = reader.author
+	l.schema = schema
+
+	return nil
+}
+
+func (l *Liquibase) ToSchema() (*Schema, error) {
+	if l.schema == nil {
+		return nil, errors.New("schema is not read")
+	}
+	return l.schema, nil
+}
+
+type lqChangeLogReader struct {
+	builder  *schemaBuilder
+	rootDir  string
+	visitSet *StringSet
+	author   string
+}
+
+func (r *lqChangeLogReader) readFile(filename string) error {
+	ext := strings.ToLower(filepath.Ext(filename))
+	if ext != ".yaml" && ext != ".yml" {
+		return fmt.Errorf("unsupported changelog format: %s", filename)
+	}
+
+	// prevent circular include
+	if absPath, err := filepath.Abs(filename); err == nil {
+		if r.visitSet.Contains(absPath) {
+			return nil
+		}
+		r.visitSet.Add(absPath)
+	}
+
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+
+	changeLog := struct {
+		DatabaseChangeLog []map[string]interface{} `yaml:"databaseChangeLog"`
+	}{}
+	if err := yaml.Unmarshal(data, &changeLog); err != nil {
+		return fmt.Errorf("%s: %s", filename, err.Error())
+	}
+
+	for _, entry := range changeLog.DatabaseChangeLog {
+		for key, value := range entry {
+			var err error
+			switch key {
+			case "changeSet":
+				err = r.readChangeSet(value)
+			case "include":
+				err = r.readInclude(filename, value)
+			case "includeAll":
+				err = r.readIncludeAll(filename, value)
+			}
+			if err != nil {
+				return fmt.Errorf("%s: %s", filename, err.Error())
+			}
+		}
+	}
+	return nil
+}
+
+// resolvePath returns path of included file.
+// path is relative to the root changelog unless relativeToChangelogFile is set.
+func (r *lqChangeLogReader) resolvePath(changeLogFile string, file string, relativeToChangelogFile bool) string {
+	if filepath.IsAbs(file) {
+		return file
+	}
+	if relativeToChangelogFile {
+		return filepath.Join(filepath.Dir(changeLogFile), file)
+	}
+	return filepath.Join(r.rootDir, file)
+}
+
+func (r *lqChangeLogReader) readInclude(changeLogFile string, value interface{}) error {
+	include := struct {
+		File                    string `yaml:"file"`
+		RelativeToChangelogFile bool   `yaml:"relativeToChangelogFile"`
+	}{}
+	if err := lqDecode(value, &include); err != nil {
+		return err
+	}
+	return r.readFile(r.resolvePath(changeLogFile, include.File, include.RelativeToChangelogFile))
+}
+
+func (r *lqChangeLogReader) readIncludeAll(changeLogFile string, value interface{}) error {
+	includeAll := struct {
+		Path                    string `yaml:"path"`
+		RelativeToChangelogFile bool   `yaml:"relativeToChangelogFile"`
+	}{}
+	if err := lqDecode(value, &includeAll); err != nil {
+		return err
+	}
+
+	dir := r.resolvePath(changeLogFile, includeAll.Path, includeAll.RelativeToChangelogFile)
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	// files are included in alphabetical order
+	filenames := make([]string, 0)
+	for _, file := range files {
+		ext := strings.ToLower(filepath.Ext(file.Name()))
+		if !file.IsDir() && (ext == ".yaml" || ext == ".yml") {
+			filenames = append(filenames, file.Name())
+		}
+	}
+	sort.Strings(filenames)
+
+	for _, filename := range filenames {
+		if err := r.readFile(filepath.Join(dir, filename)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *lqChangeLogReader) readChangeSet(value interface{}) error {
+	changeSet := LqChangeSet{}
+	if err := lqDecode(value, &changeSet); err != nil {
+		return err
+	}
+	if r.author == "" {
+		r.author = changeSet.Author
+	}
+
+	for _, change := range changeSet.Changes {
+		for key, value := range change {
+			if err := r.applyChange(key, value); err != nil {
+				return fmt.Errorf("changeSet: %s, %s: %s", changeSet.Id, key, err.Error())
+			}
+		}
+	}
+	return nil
+}
+
+// lqDecode decodes generic YAML value into Lq* struct.
+func lqDecode(value interface{}, out interface{}) error {
+	data, err := yaml.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return yaml.Unmarshal(data, out)
+}
+
+func (r *lqChangeLogReader) findColumn(tableName string, columnName string) (*Table, *Column, error) {
+	table, err := r.builder.findTable(tableName)
+	if err != nil {
+		return nil, nil, err
+	}
+	column, ok := table.ColumnByName()[columnName]
+	if !ok {
+		return nil, nil, fmt.Errorf("table: %s, column not found: %s", tableName, columnName)
+	}
+	return table, column, nil
+}
+
+func (r *lqChangeLogReader) applyChange(key string, value interface{}) error {
+	b := r.builder
+
+	switch key {
+	case "createTable":
+		change := LqCreateTable{}
+		if err := lqDecode(value, &change); err != nil {
+			return err
+		}
+		table := &Table{
+			Name:        change.TableName,
+			Description: change.Remarks,
+		}
+		b.addTable(table)
+		for _, col := range change.Columns {
+			if err := r.addColumn(table, col["column"]); err != nil {
+				return err
+			}
+		}
+
+	case "dropTable":
+		change := LqDropTable{}
+		if err := lqDecode(value, &change); err != nil {
+			return err
+		}
+		if _, err := b.findTable(change.TableName); err != nil {
+			return err
+		}
+		b.removeTable(change.TableName)
+
+	case "renameTable":
+		change := LqRenameTable{}
+		if err := lqDecode(value, &change); err != nil {
+			return err
+		}
+		return b.renameTable(change.OldTableName, change.NewTableName)
+
+	case "setTableRemarks":
+		change := LqSetTableRemarks{}
+		if err := lqDecode(value, &change); err != nil {
+			return err
+		}
+		table, err := b.findTable(change.TableName)
+		if err != nil {
+			return err
+		}
+		table.Description = change.Remarks
+
+	case "addColumn":
+		change := LqAddColumn{}
+		if err := lqDecode(value, &change); err != nil {
+			return err
+		}
+		table, err := b.findTable(change.TableName)
+		if err != nil {
+			return err
+		}
+		for _, col := range change.Columns {
+			if err := r.addColumn(table, col["column"]); err != nil {
+				return err
+			}
+		}
+
+	case "dropColumn":
+		change := LqDropColumn{}
+		if err := lqDecode(value, &change); err != nil {
+			return err
+		}
+		table, err := b.findTable(change.TableName)
+		if err != nil {
+			return err
+		}
+		columnNames := make([]string, 0)
+		if change.ColumnName != "" {
+			columnNames = append(columnNames, change.ColumnName)
+		}
+		for _, col := range change.Columns {
+			if lc := col["column"]; lc != nil {
+				columnNames = append(columnNames, lc.Name)
+			}
+		}
+		for _, columnName := range columnNames {
+			if err := b.dropColumn(table, columnName); err != nil {
+				return err
+			}
+		}
+
+	case "renameColumn":
+		change := LqRenameColumn{}
+		if err := lqDecode(value, &change); err != nil {
+			return err
+		}
+		table, err := b.findTable(change.TableName)
+		if err != nil {
+			return err
+		}
+		return b.renameColumn(table, change.OldColumnName, change.NewColumnName)
+
+	case "modifyDataType":
+		change := LqModifyDataType{}
+		if err := lqDecode(value, &change); err != nil {
+			return err
+		}
+		_, column, err := r.findColumn(change.TableName, change.ColumnName)
+		if err != nil {
+			return err
+		}
+		column.Type, column.Size, column.Scale = fromLiquibaseType(change.NewDataType)
+
+	case "setColumnRemarks":
+		change := LqSetColumnRemarks{}
+		if err := lqDecode(value, &change); err != nil {
+			return err
+		}
+		_, column, err := r.findColumn(change.TableName, change.ColumnName)
+		if err != nil {
+			return err
+		}
+		column.Description = change.Remarks
+
+	case "addNotNullConstraint":
+		change := LqAddNotNullConstraint{}
+		if err := lqDecode(value, &change); err != nil {
+			return err
+		}
+		_, column, err := r.findColumn(change.TableName, change.ColumnName)
+		if err != nil {
+			return err
+		}
+		column.Nullable = false
+
+	case "dropNotNullConstraint":
+		change := LqDropNotNullConstraint{}
+		if err := lqDecode(value, &change); err != nil {
+			return err
+		}
+		_, column, err := r.findColumn(change.TableName, change.ColumnName)
+		if err != nil {
+			return err
+		}
+		column.Nullable = true
+
+	case "addAutoIncrement":
+		change := LqAddAutoIncrement{}
+		if err := lqDecode(value, &change); err != nil {
+			return err
+		}
+		_, column, err := r.findColumn(change.TableName, change.ColumnName)
+		if err != nil {
+			return err
+		}
+		column.AutoIncremental = true
+
+	case "addDefaultValue":
+		change := LqAddDefaultValue{}
+		if err := lqDecode(value, &change); err != nil {
+			return err
+		}
+		_, column, err := r.findColumn(change.TableName, change.ColumnName)
+		if err != nil {
+			return err
+		}
+		column.DefaultValue = fromLqDefaultValue(&LqColumn{
+			DefaultValue:         change.DefaultValue,
+			DefaultValueBoolean:  change.DefaultValueBoolean,
+			DefaultValueNumeric:  change.DefaultValueNumeric,
+			DefaultValueDate:     change.DefaultValueDate,
+			DefaultValueComputed: change.DefaultValueComputed,
+		})
+
+	case "dropDefaultValue":
+		change := LqDropDefaultValue{}
+		if err := lqDecode(value, &change); err != nil {
+			return err
+		}
+		_, column, err := r.findColumn(change.TableName, change.ColumnName)
+		if err != nil {
+			return err
+		}
+		column.DefaultValue = ""
+
+	case "addPrimaryKey":
+		change := LqAddPrimaryKey{}
+		if err := lqDecode(value, &change); err != nil {
+			return err
+		}
+		table, err := b.findTable(change.TableName)
+		if err != nil {
+			return err
+		}
+		return b.addConstraint(table, &tableConstraint{
+			columns: splitLqColumnNames(change.ColumnNames),
+			primary: true,
+		})
+
+	case "dropPrimaryKey":
+		change := LqDropPrimaryKey{}
+		if err := lqDecode(value, &change); err != nil {
+			return err
+		}
+		table, err := b.findTable(change.TableName)
+		if err != nil {
+			return err
+		}
+		b.dropPrimaryKey(table)
+
+	case "addUniqueConstraint":
+		change := LqAddUniqueConstraint{}
+		if err := lqDecode(value, &change); err != nil {
+			return err
+		}
+		table, err := b.findTable(change.TableName)
+		if err != nil {
+			return err
+		}
+		return b.addConstraint(table, &tableConstraint{
+			name:    change.ConstraintName,
+			columns: splitLqColumnNames(change.ColumnNames),
+			unique:  true,
+		})
+
+	case "dropUniqueConstraint":
+		change := LqDropUniqueConstraint{}
+		if err := lqDecode(value, &change); err != nil {
+			return err
+		}
+		table, err := b.findTable(change.TableName)
+		if err != nil {
+			return err
+		}
+		return b.dropIndex(table, change.ConstraintName)
+
+	case "addForeignKeyConstraint":
+		change := LqAddForeignKeyConstraint{}
+		if err := lqDecode(value, &change); err != nil {
+			return err
+		}
+		table, err := b.findTable(change.BaseTableName)
+		if err != nil {
+			return err
+		}
+		columns := splitLqColumnNames(change.BaseColumnNames)
+		refColumns := splitLqColumnNames(change.ReferencedColumnNames)
+		if len(columns) != len(refColumns) {
+			return fmt.Errorf("foreign key column count mismatch: %s", change.ConstraintName)
+		}
+		return b.addConstraint(table, &tableConstraint{
+			name:       change.ConstraintName,
+			columns:    columns,
+			foreignKey: true,
+			refTable:   change.ReferencedTableName,
+			refColumns: refColumns,
+		})
+
+	case "dropForeignKeyConstraint":
+		change := LqDropForeignKeyConstraint{}
+		if err := lqDecode(value, &change); err != nil {
+			return err
+		}
+		table, err := b.findTable(change.BaseTableName)
+		if err != nil {
+			return err
+		}
+		return b.dropForeignKey(table, change.ConstraintName)
+
+	case "createIndex":
+		change := LqCreateIndex{}
+		if err := lqDecode(value, &change); err != nil {
+			return err
+		}
+		table, err := b.findTable(change.TableName)
+		if err != nil {
+			return err
+		}
+		columns := make([]string, 0)
+		for _, col := range change.Columns {
+			if lc := col["column"]; lc != nil {
+				columns = append(columns, lc.Name)
+			}
+		}
+		return b.addConstraint(table, &tableConstraint{
+			name:    change.IndexName,
+			columns: columns,
+			unique:  change.Unique != nil && *change.Unique,
+		})
+
+	case "dropIndex":
+		change := LqDropIndex{}
+		if err := lqDecode(value, &change); err != nil {
+			return err
+		}
+		table, err := b.findTable(change.TableName)
+		if err != nil {
+			return err
+		}
+		return b.dropIndex(table, change.IndexName)
+
+	default:
+		log.Printf("unsupported liquibase change is skipped: %s", key)
+	}
+	return nil
+}
+
+// addColumn adds column of createTable or addColumn change.
+func (r *lqChangeLogReader) addColumn(table *Table, lc *LqColumn) error {
+	if lc == nil {
+		return nil
+	}
+	if _, ok := table.ColumnByName()[lc.Name]; ok {
+		return fmt.Errorf("table: %s, duplicate column: %s", table.Name, lc.Name)
+	}
+
+	typ, size, scale := fromLiquibaseType(lc.Type)
+	column := &Column{
+		Name:            lc.Name,
+		Type:            typ,
+		Description:     lc.Remarks,
+		Size:            size,
+		Scale:           scale,
+		Nullable:        true,
+		AutoIncremental: lc.AutoIncrement != nil && *lc.AutoIncrement,
+		DefaultValue:    fromLqDefaultValue(lc),
+	}
+
+	first, after := false, lc.AfterColumn
+	if lc.BeforeColumn != "" {
+		for i, c := range table.Columns {
+			if c.Name == lc.BeforeColumn {
+				first = i == 0
+				after = TernaryString(i > 0, table.Columns[i-1].Name, "")
+				break
+			}
+		}
+	}
+	r.builder.insertColumn(table, column, first, after)
+
+	if constraints := lc.Constraints; constraints != nil {
+		if constraints.Nullable != nil {
+			column.Nullable = *constraints.Nullable
+		}
+		if constraints.PrimaryKey != nil && *constraints.PrimaryKey {
+			if err := r.builder.addConstraint(table, &tableConstraint{
+				columns: []string{column.Name},
+				primary: true,
+			}); err != nil {
+				return err
+			}
+		}
+		if constraints.Unique != nil && *constraints.Unique {
+			if err := r.builder.addConstraint(table, &tableConstraint{
+				name:    constraints.UniqueConstraintName,
+				columns: []string{column.Name},
+				unique:  true,
+			}); err != nil {
+				return err
+			}
+		}
+
+		// references: "table(column)"
+		refTable, refColumn := constraints.ReferencedTableName, constraints.ReferencedColumnNames
+		if refTable == "" && constraints.References != "" {
+			if open := strings.Index(constraints.References, "("); open > 0 {
+				refTable = strings.TrimSpace(constraints.References[:open])
+				refColumn = strings.Trim(constraints.References[open:], "() ")
+			}
+		}
+		if refTable != "" && refColumn != "" {
+			if err := r.builder.addConstraint(table, &tableConstraint{
+				name:       constraints.ForeignKeyName,
+				columns:    []string{column.Name},
+				foreignKey: true,
+				refTable:   refTable,
+				refColumns: []string{refColumn},
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// splitLqColumnNames splits comma separated column names.
+func splitLqColumnNames(columnNames string) []string {
+	result := make([]string, 0)
+	for _, name := range strings.Split(columnNames, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			result = append(result, name)
+		}
+	}
+	return result
+}
+
+func fromLqDefaultValue(lc *LqColumn) string {
+	switch {
+	case lc.DefaultValue != "":
+		return lc.DefaultValue
+	case lc.DefaultValueBoolean != nil:
+		return strconv.FormatBool(*lc.DefaultValueBoolean)
+	case lc.DefaultValueNumeric != nil:
+		return fmt.Sprintf("%v", lc.DefaultValueNumeric)
+	case lc.DefaultValueDate != "":
+		return lc.DefaultValueDate
+	default:
+		return lc.DefaultValueComputed
+	}
+}
+
+// fromLiquibaseType converts liquibase column type to octopus type, size and scale.
+// it is the reverse of getLiquibaseType.
+func fromLiquibaseType(liquibaseType string) (string, uint16, uint16) {
+	typ, size, scale := ParseType(strings.ToLower(strings.TrimSpace(liquibaseType)))
+
+	switch typ {
+	case "varchar", "nvarchar", "char", "nchar", "varchar2", "nvarchar2", "character varying":
+		return ColTypeString, size, scale
+	case "clob", "nclob", "text", "tinytext", "mediumtext", "longtext":
+		return ColTypeText, 0, 0
+	case "boolean", "bool", "bit":
+		return ColTypeBoolean, 0, 0
+	case "bigint", "int8":
+		return ColTypeLong, 0, 0
+	case "int", "integer", "int4", "smallint", "tinyint", "mediumint":
+		return ColTypeInt, 0, 0
+	case "decimal", "numeric", "number":
+		return ColTypeDecimal, size, scale
+	case "float", "real":
+		return ColTypeFloat, size, scale
+	case "double", "double precision":
+		return ColTypeDouble, size, scale
+	case "datetime", "timestamp":
+		return ColTypeDateTime, 0, 0
+	case "date":
+		return ColTypeDate, 0, 0
+	case "time":
+		return ColTypeTime, 0, 0
+	case "blob", "longblob", "mediumblob", "binary", "varbinary", "bytea":
+		return ColTypeBlob, size, 0
+	default:
+		return typ, size, scale
+	}
+}
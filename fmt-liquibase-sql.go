@@ -0,0 +1,544 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const (
+	LqFormatSql = "sql"
+
+	DbmsMysql      = "mysql"
+	DbmsPostgresql = "postgresql"
+)
+
+// lqSqlWriter writes changelog as liquibase formatted SQL for a single dialect.
+// schema is used to find full column definitions which MySQL requires to modify a column.
+type lqSqlWriter struct {
+	dbms      string
+	schema    *Schema
+	oldSchema *Schema
+}
+
+func newLqSqlWriter(dbms string, schema *Schema, oldSchema *Schema) (*lqSqlWriter, error) {
+	if dbms == "" {
+		dbms = DbmsMysql
+	}
+	if dbms != DbmsMysql && dbms != DbmsPostgresql {
+		return nil, fmt.Errorf("unsupported dbms for formatted sql: %s", dbms)
+	}
+	return &lqSqlWriter{
+		dbms:      dbms,
+		schema:    schema,
+		oldSchema: oldSchema,
+	}, nil
+}
+
+func (w *lqSqlWriter) marshal(changeLog *LqYaml) ([]byte, error) {
+	lines := []string{"--liquibase formatted sql"}
+
+	for _, entry := range changeLog.DatabaseChangeLog {
+		entryMap, ok := entry.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		changeSet, ok := entryMap["changeSet"].(*LqChangeSet)
+		if !ok {
+			continue
+		}
+
+		// changeSet header
+		header := fmt.Sprintf("--changeset %s:%s", strings.ReplaceAll(changeSet.Author, " ", "_"), changeSet.Id)
+		if dbms, ok := changeSet.PreConditions["dbms"].(map[string]string); ok {
+			header += " dbms:" + strings.ReplaceAll(dbms["type"], " ", "")
+		}
+		lines = append(lines, "", header)
+
+		preConditions, err := w.preConditions(changeSet.PreConditions)
+		if err != nil {
+			return nil, fmt.Errorf("changeSet: %s, %s", changeSet.Id, err.Error())
+		}
+		lines = append(lines, preConditions...)
+
+		for _, change := range changeSet.Changes {
+			for key, value := range change {
+				statements, err := w.toSql(key, value, w.schema)
+				if err != nil {
+					return nil, fmt.Errorf("changeSet: %s, %s", changeSet.Id, err.Error())
+				}
+				if key == "createTable" {
+					statements = w.modifySql(statements, changeSet.ModifySql)
+				}
+				lines = append(lines, statements...)
+			}
+		}
+
+		// use explicit rollback changes if exists. otherwise derive from changes.
+		rollbackChanges := changeSet.Rollback
+		if len(rollbackChanges) == 0 {
+			derived := &LqChangeSet{Changes: changeSet.Changes}
+			derived.deriveRollback()
+			rollbackChanges = derived.Rollback
+		}
+		rollbacks := make([]string, 0)
+		for _, change := range rollbackChanges {
+			for key, value := range change {
+				statements, err := w.toSql(key, value, w.getRollbackSchema())
+				if err != nil {
+					return nil, fmt.Errorf("changeSet: %s, %s", changeSet.Id, err.Error())
+				}
+				rollbacks = append(rollbacks, statements...)
+			}
+		}
+		for _, rollback := range rollbacks {
+			lines = append(lines, "--rollback "+strings.ReplaceAll(rollback, "\n", " "))
+		}
+	}
+
+	return []byte(strings.Join(lines, "\n") + "\n"), nil
+}
+
+// preConditions converts tableExists/columnExists preconditions to sql check lines.
+// dbms precondition is written in changeSet header.
+func (w *lqSqlWriter) preConditions(preConditions map[string]interface{}) ([]string, error) {
+	keys := make([]string, 0)
+	for key := range preConditions {
+		if key != "dbms" && key != "onFail" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	checks := make([]string, 0)
+	for _, key := range keys {
+		sqlChecks, err := w.sqlChecks(key, preConditions[key], false)
+		if err != nil {
+			return nil, err
+		}
+		checks = append(checks, sqlChecks...)
+	}
+	if len(checks) == 0 {
+		return checks, nil
+	}
+
+	header := "--preconditions"
+	if onFail, ok := preConditions["onFail"].(string); ok {
+		header += " onFail:" + onFail
+	}
+	return append([]string{header}, checks...), nil
+}
+
+// sqlChecks returns precondition-sql-check lines of the precondition.
+// conditions in 'not' are checked one by one, so none of them should be true.
+func (w *lqSqlWriter) sqlChecks(key string, value interface{}, negate bool) ([]string, error) {
+	currentSchema := "DATABASE()"
+	if w.dbms == DbmsPostgresql {
+		currentSchema = "current_schema()"
+	}
+
+	var query string
+	switch c := value.(type) {
+	case []map[string]interface{}:
+		if (key != "and" && key != "not") || negate {
+			return nil, fmt.Errorf("unsupported precondition: %s", key)
+		}
+		result := make([]string, 0)
+		for _, condition := range c {
+			for conditionKey, conditionValue := range condition {
+				checks, err := w.sqlChecks(conditionKey, conditionValue, key == "not")
+				if err != nil {
+					return nil, err
+				}
+				result = append(result, checks...)
+			}
+		}
+		return result, nil
+	case *LqTableExists:
+		query = fmt.Sprintf("SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = %s AND table_name = %s",
+			currentSchema, w.quoteString(c.TableName))
+	case *LqColumnExists:
+		query = fmt.Sprintf("SELECT COUNT(*) FROM information_schema.columns WHERE table_schema = %s AND table_name = %s AND column_name = %s",
+			currentSchema, w.quoteString(c.TableName), w.quoteString(c.ColumnName))
+	default:
+		return nil, fmt.Errorf("unsupported precondition: %s", key)
+	}
+
+	expectedResult := 1
+	if negate {
+		expectedResult = 0
+	}
+	return []string{fmt.Sprintf("--precondition-sql-check expectedResult:%d %s", expectedResult, query)}, nil
+}
+
+// modifySql appends modifySql values of the dbms to the last statement.
+func (w *lqSqlWriter) modifySql(statements []string, modifySqls []*LqModifySql) []string {
+	if len(statements) == 0 {
+		return statements
+	}
+	last := len(statements) - 1
+	for _, modifySql := range modifySqls {
+		if modifySql.Append == nil || (modifySql.Dbms != "" && modifySql.Dbms != w.dbms) {
+			continue
+		}
+		statements[last] = strings.TrimSuffix(statements[last], ";") + modifySql.Append.Value + ";"
+	}
+	return statements
+}
+
+func (w *lqSqlWriter) getRollbackSchema() *Schema {
+	if w.oldSchema != nil {
+		return w.oldSchema
+	}
+	return w.schema
+}
+
+func (w *lqSqlWriter) quote(name string) string {
+	if w.dbms == DbmsMysql {
+		return "`" + name + "`"
+	}
+	return `"` + name + `"`
+}
+
+func (w *lqSqlWriter) quoteNames(names string) string {
+	result := make([]string, 0)
+	for _, name := range splitLqColumnNames(names) {
+		result = append(result, w.quote(name))
+	}
+	return strings.Join(result, ", ")
+}
+
+func (w *lqSqlWriter) quoteString(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+func (w *lqSqlWriter) alterTable(tableName string, spec string) string {
+	return fmt.Sprintf("ALTER TABLE %s %s;", w.quote(tableName), spec)
+}
+
+// toSql converts a single change to SQL statements.
+func (w *lqSqlWriter) toSql(key string, value interface{}, schema *Schema) ([]string, error) {
+	isMysql := w.dbms == DbmsMysql
+
+	switch c := value.(type) {
+	case *LqCreateTable:
+		return w.createTable(c), nil
+
+	case *LqDropTable:
+		return []string{fmt.Sprintf("DROP TABLE %s;", w.quote(c.TableName))}, nil
+
+	case *LqRenameTable:
+		if isMysql {
+			return []string{fmt.Sprintf("RENAME TABLE %s TO %s;", w.quote(c.OldTableName), w.quote(c.NewTableName))}, nil
+		}
+		return []string{w.alterTable(c.OldTableName, "RENAME TO "+w.quote(c.NewTableName))}, nil
+
+	case *LqSetTableRemarks:
+		if isMysql {
+			return []string{w.alterTable(c.TableName, "COMMENT = "+w.quoteString(c.Remarks))}, nil
+		}
+		return []string{fmt.Sprintf("COMMENT ON TABLE %s IS %s;", w.quote(c.TableName), w.quoteString(c.Remarks))}, nil
+
+	case *LqAddColumn:
+		specs := make([]string, 0)
+		comments := make([]string, 0)
+		for _, col := range c.Columns {
+			lc := col["column"]
+			spec := "ADD " + TernaryString(isMysql, "", "COLUMN ") + w.columnDefinition(lc, true)
+			if isMysql && lc.AfterColumn != "" {
+				spec += " AFTER " + w.quote(lc.AfterColumn)
+			}
+			specs = append(specs, spec)
+			if !isMysql && lc.Remarks != "" {
+				comments = append(comments, w.columnComment(c.TableName, lc.Name, lc.Remarks))
+			}
+		}
+		return append([]string{w.alterTable(c.TableName, strings.Join(specs, ", "))}, comments...), nil
+
+	case *LqDropColumn:
+		specs := make([]string, 0)
+		if c.ColumnName != "" {
+			specs = append(specs, "DROP COLUMN "+w.quote(c.ColumnName))
+		}
+		for _, col := range c.Columns {
+			specs = append(specs, "DROP COLUMN "+w.quote(col["column"].Name))
+		}
+		return []string{w.alterTable(c.TableName, strings.Join(specs, ", "))}, nil
+
+	case *LqSetColumnRemarks:
+		if isMysql {
+			lc := w.findLqColumn(schema, c.TableName, c.ColumnName, "")
+			lc.Remarks = c.Remarks
+			return []string{w.alterTable(c.TableName, "MODIFY "+w.columnDefinition(lc, false))}, nil
+		}
+		return []string{w.columnComment(c.TableName, c.ColumnName, c.Remarks)}, nil
+
+	case *LqRenameColumn:
+		if isMysql {
+			lc := w.findLqColumn(schema, c.TableName, c.NewColumnName, c.ColumnDataType)
+			lc.Name = c.NewColumnName
+			return []string{w.alterTable(c.TableName,
+				fmt.Sprintf("CHANGE %s %s", w.quote(c.OldColumnName), w.columnDefinition(lc, false)))}, nil
+		}
+		return []string{w.alterTable(c.TableName,
+			fmt.Sprintf("RENAME COLUMN %s TO %s", w.quote(c.OldColumnName), w.quote(c.NewColumnName)))}, nil
+
+	case *LqModifyDataType:
+		if isMysql {
+			lc := w.findLqColumn(schema, c.TableName, c.ColumnName, c.NewDataType)
+			lc.Type = c.NewDataType
+			return []string{w.alterTable(c.TableName, "MODIFY "+w.columnDefinition(lc, false))}, nil
+		}
+		return []string{w.alterTable(c.TableName,
+			fmt.Sprintf("ALTER COLUMN %s TYPE %s", w.quote(c.ColumnName), w.toSqlType(c.NewDataType)))}, nil
+
+	case *LqAddNotNullConstraint:
+		result := make([]string, 0)
+		if c.DefaultNullValue != "" {
+			result = append(result, fmt.Sprintf("UPDATE %s SET %s = %s WHERE %s IS NULL;",
+				w.quote(c.TableName), w.quote(c.ColumnName), w.quoteString(c.DefaultNullValue), w.quote(c.ColumnName)))
+		}
+		if isMysql {
+			lc := w.findLqColumn(schema, c.TableName, c.ColumnName, c.ColumnDataType)
+			lc.Constraints = &LqConstraints{Nullable: NewBool(false)}
+			return append(result, w.alterTable(c.TableName, "MODIFY "+w.columnDefinition(lc, false))), nil
+		}
+		return append(result, w.alterTable(c.TableName,
+			fmt.Sprintf("ALTER COLUMN %s SET NOT NULL", w.quote(c.ColumnName)))), nil
+
+	case *LqDropNotNullConstraint:
+		if isMysql {
+			lc := w.findLqColumn(schema, c.TableName, c.ColumnName, c.ColumnDataType)
+			lc.Constraints = nil
+			return []string{w.alterTable(c.TableName, "MODIFY "+w.columnDefinition(lc, false)+" NULL")}, nil
+		}
+		return []string{w.alterTable(c.TableName,
+			fmt.Sprintf("ALTER COLUMN %s DROP NOT NULL", w.quote(c.ColumnName)))}, nil
+
+	case *LqAddAutoIncrement:
+		if isMysql {
+			lc := w.findLqColumn(schema, c.TableName, c.ColumnName, c.ColumnDataType)
+			lc.AutoIncrement = NewBool(true)
+			return []string{w.alterTable(c.TableName, "MODIFY "+w.columnDefinition(lc, false))}, nil
+		}
+		return []string{w.alterTable(c.TableName,
+			fmt.Sprintf("ALTER COLUMN %s ADD GENERATED BY DEFAULT AS IDENTITY", w.quote(c.ColumnName)))}, nil
+
+	case *LqAddDefaultValue:
+		defaultValue := w.defaultValue(&LqColumn{
+			Type:                 c.ColumnDataType,
+			DefaultValue:         c.DefaultValue,
+			DefaultValueBoolean:  c.DefaultValueBoolean,
+			DefaultValueNumeric:  c.DefaultValueNumeric,
+			DefaultValueDate:     c.DefaultValueDate,
+			DefaultValueComputed: c.DefaultValueComputed,
+		})
+		return []string{w.alterTable(c.TableName,
+			fmt.Sprintf("ALTER %s%s SET DEFAULT %s", TernaryString(isMysql, "", "COLUMN "), w.quote(c.ColumnName), defaultValue))}, nil
+
+	case *LqDropDefaultValue:
+		return []string{w.alterTable(c.TableName,
+			fmt.Sprintf("ALTER %s%s DROP DEFAULT", TernaryString(isMysql, "", "COLUMN "), w.quote(c.ColumnName)))}, nil
+
+	case *LqAddPrimaryKey:
+		if isMysql {
+			return []string{w.alterTable(c.TableName, fmt.Sprintf("ADD PRIMARY KEY (%s)", w.quoteNames(c.ColumnNames)))}, nil
+		}
+		return []string{w.alterTable(c.TableName, fmt.Sprintf("ADD CONSTRAINT %s PRIMARY KEY (%s)",
+			w.quote(c.TableName+"_pkey"), w.quoteNames(c.ColumnNames)))}, nil
+
+	case *LqDropPrimaryKey:
+		if isMysql {
+			return []string{w.alterTable(c.TableName, "DROP PRIMARY KEY")}, nil
+		}
+		return []string{w.alterTable(c.TableName, "DROP CONSTRAINT "+w.quote(c.TableName+"_pkey"))}, nil
+
+	case *LqAddUniqueConstraint:
+		return []string{w.alterTable(c.TableName, fmt.Sprintf("ADD CONSTRAINT %s UNIQUE (%s)",
+			w.quote(c.ConstraintName), w.quoteNames(c.ColumnNames)))}, nil
+
+	case *LqDropUniqueConstraint:
+		if isMysql {
+			return []string{w.alterTable(c.TableName, "DROP INDEX "+w.quote(c.ConstraintName))}, nil
+		}
+		return []string{w.alterTable(c.TableName, "DROP CONSTRAINT "+w.quote(c.ConstraintName))}, nil
+
+	case *LqAddForeignKeyConstraint:
+		spec := fmt.Sprintf("ADD CONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s (%s)",
+			w.quote(c.ConstraintName), w.quoteNames(c.BaseColumnNames),
+			w.quote(c.ReferencedTableName), w.quoteNames(c.ReferencedColumnNames))
+		if c.OnDelete != "" {
+			spec += " ON DELETE " + c.OnDelete
+		}
+		if c.OnUpdate != "" {
+			spec += " ON UPDATE " + c.OnUpdate
+		}
+		return []string{w.alterTable(c.BaseTableName, spec)}, nil
+
+	case *LqDropForeignKeyConstraint:
+		if isMysql {
+			return []string{w.alterTable(c.BaseTableName, "DROP FOREIGN KEY "+w.quote(c.ConstraintName))}, nil
+		}
+		return []string{w.alterTable(c.BaseTableName, "DROP CONSTRAINT "+w.quote(c.ConstraintName))}, nil
+
+	case *LqCreateIndex:
+		columns := make([]string, 0)
+		for _, col := range c.Columns {
+			columns = append(columns, w.quote(col["column"].Name))
+		}
+		unique := c.Unique != nil && *c.Unique
+		return []string{fmt.Sprintf("CREATE %sINDEX %s ON %s (%s);",
+			TernaryString(unique, "UNIQUE ", ""), w.quote(c.IndexName), w.quote(c.TableName), strings.Join(columns, ", "))}, nil
+
+	case *LqDropIndex:
+		if isMysql {
+			return []string{fmt.Sprintf("DROP INDEX %s ON %s;", w.quote(c.IndexName), w.quote(c.TableName))}, nil
+		}
+		return []string{fmt.Sprintf("DROP INDEX %s;", w.quote(c.IndexName))}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported change: %s", key)
+}
+
+func (w *lqSqlWriter) createTable(c *LqCreateTable) []string {
+	isMysql := w.dbms == DbmsMysql
+
+	definitions := make([]string, 0)
+	primaryKeys := make([]string, 0)
+	comments := make([]string, 0)
+	for _, col := range c.Columns {
+		lc := col["column"]
+		definitions = append(definitions, "  "+w.columnDefinition(lc, true))
+		if lc.Constraints != nil && lc.Constraints.PrimaryKey != nil && *lc.Constraints.PrimaryKey {
+			primaryKeys = append(primaryKeys, w.quote(lc.Name))
+		}
+		if !isMysql && lc.Remarks != "" {
+			comments = append(comments, w.columnComment(c.TableName, lc.Name, lc.Remarks))
+		}
+	}
+	if len(primaryKeys) > 0 {
+		definitions = append(definitions, fmt.Sprintf("  PRIMARY KEY (%s)", strings.Join(primaryKeys, ", ")))
+	}
+
+	stmt := fmt.Sprintf("CREATE TABLE %s (\n%s\n)", w.quote(c.TableName), strings.Join(definitions, ",\n"))
+	if isMysql && c.Remarks != "" {
+		stmt += " COMMENT = " + w.quoteString(c.Remarks)
+	}
+	result := []string{stmt + ";"}
+
+	if !isMysql && c.Remarks != "" {
+		result = append(result, fmt.Sprintf("COMMENT ON TABLE %s IS %s;", w.quote(c.TableName), w.quoteString(c.Remarks)))
+	}
+	return append(result, comments...)
+}
+
+// columnDefinition returns column definition.
+// primary key is defined as a table constraint.
+func (w *lqSqlWriter) columnDefinition(lc *LqColumn, withUnique bool) string {
+	isMysql := w.dbms == DbmsMysql
+	autoIncrement := lc.AutoIncrement != nil && *lc.AutoIncrement
+
+	params := []string{w.quote(lc.Name), w.toSqlType(lc.Type)}
+	if constraints := lc.Constraints; constraints != nil {
+		primaryKey := constraints.PrimaryKey != nil && *constraints.PrimaryKey
+		if primaryKey || (constraints.Nullable != nil && !*constraints.Nullable) {
+			params = append(params, "NOT NULL")
+		}
+		if withUnique && constraints.Unique != nil && *constraints.Unique {
+			params = append(params, "UNIQUE")
+		}
+	}
+	if autoIncrement {
+		params = append(params, TernaryString(isMysql, "AUTO_INCREMENT", "GENERATED BY DEFAULT AS IDENTITY"))
+	}
+	if defaultValue := w.defaultValue(lc); defaultValue != "" {
+		params = append(params, "DEFAULT "+defaultValue)
+	}
+	if isMysql && lc.Remarks != "" {
+		params = append(params, "COMMENT "+w.quoteString(lc.Remarks))
+	}
+	return strings.Join(params, " ")
+}
+
+func (w *lqSqlWriter) columnComment(tableName string, columnName string, remarks string) string {
+	return fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s;", w.quote(tableName), w.quote(columnName), w.quoteString(remarks))
+}
+
+func (w *lqSqlWriter) defaultValue(lc *LqColumn) string {
+	switch {
+	case lc.DefaultValue != "":
+		return w.quoteString(lc.DefaultValue)
+	case lc.DefaultValueBoolean != nil:
+		if w.dbms == DbmsMysql {
+			return BoolToString(*lc.DefaultValueBoolean, "1", "0")
+		}
+		return BoolToString(*lc.DefaultValueBoolean, "TRUE", "FALSE")
+	case lc.DefaultValueNumeric != nil:
+		value := reflect.ValueOf(lc.DefaultValueNumeric)
+		if value.Kind() == reflect.Ptr {
+			value = value.Elem()
+		}
+		return fmt.Sprintf("%v", value.Interface())
+	case lc.DefaultValueDate != "":
+		return w.quoteString(lc.DefaultValueDate)
+	default:
+		return lc.DefaultValueComputed
+	}
+}
+
+// findLqColumn returns column definition from schema.
+// if column is not found, a column with dataType is returned.
+func (w *lqSqlWriter) findLqColumn(schema *Schema, tableName string, columnName string, dataType string) *LqColumn {
+	if schema != nil {
+		if table, ok := schema.TableByName()[tableName]; ok {
+			if column, ok := table.ColumnByName()[columnName]; ok {
+				if lc, err := newLqColumn(column, true, true); err == nil {
+					return lc
+				}
+			}
+		}
+	}
+	return &LqColumn{Name: columnName, Type: dataType}
+}
+
+// toSqlType converts liquibase type to dialect type.
+func (w *lqSqlWriter) toSqlType(liquibaseType string) string {
+	typ, size, scale := ParseType(strings.ToLower(liquibaseType))
+	if typ == "" {
+		return liquibaseType
+	}
+
+	name := typ
+	if w.dbms == DbmsMysql {
+		switch typ {
+		case "clob":
+			name = "longtext"
+		case "boolean":
+			name = "bit(1)"
+		case "blob":
+			name = "longblob"
+		}
+	} else {
+		switch typ {
+		case "clob":
+			name = "text"
+		case "int":
+			name = "integer"
+		case "double":
+			name = "double precision"
+		case "datetime":
+			name = "timestamp"
+		case "blob":
+			name = "bytea"
+		}
+	}
+
+	if size == 0 || strings.Contains(name, "(") {
+		return name
+	}
+	if scale > 0 {
+		return fmt.Sprintf("%s(%d,%d)", name, size, scale)
+	}
+	return fmt.Sprintf("%s(%d)", name, size)
+}
@@ -1,6 +1,9 @@
 package main
 
 import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"github.com/google/go-cmp/cmp"
 	"gopkg.in/yaml.v2"
@@ -10,6 +13,7 @@ import (
 	"path"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type LqYaml struct {
@@ -36,7 +40,14 @@ type LqChangeSet struct {
 	Id            string                   `yaml:"id"`
 	Author        string                   `yaml:"author,omitempty"`
 	PreConditions map[string]interface{}   `yaml:"preConditions,omitempty"`
+	Labels        string                   `yaml:"labels,omitempty"`
+	Context       string                   `yaml:"context,omitempty"`
 	Changes       []map[string]interface{} `yaml:"changes,omitempty"`
+	Rollback      []map[string]interface{} `yaml:"rollback,omitempty"`
+	ModifySql     []*LqModifySql           `yaml:"modifySql,omitempty"`
+
+	// name of the table which changes are applied to
+	tableName string
 }
 
 func newLqChangeSet(id string, author string) *LqChangeSet {
@@ -52,6 +63,81 @@ func (s *LqChangeSet) Append(key string, change interface{}) {
 	s.Changes = append(s.Changes, map[string]interface{}{key: change})
 }
 
+func (s *LqChangeSet) AppendRollback(key string, change interface{}) {
+	s.Rollback = append(s.Rollback, map[string]interface{}{key: change})
+}
+
+// deriveRollback sets rollback changes which revert changes in reverse order.
+func (s *LqChangeSet) deriveRollback() {
+	for i := len(s.Changes) - 1; i >= 0; i-- {
+		for key, value := range s.Changes[i] {
+			if inverseKey, inverse, ok := inverseLqChange(key, value); ok {
+				s.AppendRollback(inverseKey, inverse)
+			}
+		}
+	}
+}
+
+// inverseLqChange returns the change which reverts the given change.
+// returns false if the change cannot be reverted without the previous schema.
+func inverseLqChange(key string, value interface{}) (string, interface{}, bool) {
+	switch c := value.(type) {
+	case *LqCreateTable:
+		return "dropTable", &LqDropTable{TableName: c.TableName}, true
+	case *LqRenameTable:
+		return "renameTable", newRenameTable(c.OldTableName, c.NewTableName), true
+	case *LqAddColumn:
+		if len(c.Columns) == 1 {
+			return "dropColumn", &LqDropColumn{TableName: c.TableName, ColumnName: c.Columns[0]["column"].Name}, true
+		}
+		columns := make([]map[string]*LqColumn, 0)
+		for _, col := range c.Columns {
+			columns = append(columns, map[string]*LqColumn{"column": {Name: col["column"].Name}})
+		}
+		return "dropColumn", &LqDropColumn{TableName: c.TableName, Columns: columns}, true
+	case *LqRenameColumn:
+		return "renameColumn", &LqRenameColumn{
+			TableName:      c.TableName,
+			NewColumnName:  c.OldColumnName,
+			OldColumnName:  c.NewColumnName,
+			ColumnDataType: c.ColumnDataType,
+		}, true
+	case *LqAddNotNullConstraint:
+		return "dropNotNullConstraint", &LqDropNotNullConstraint{
+			TableName:      c.TableName,
+			ColumnName:     c.ColumnName,
+			ColumnDataType: c.ColumnDataType,
+		}, true
+	case *LqDropNotNullConstraint:
+		return "addNotNullConstraint", &LqAddNotNullConstraint{
+			TableName:      c.TableName,
+			ColumnName:     c.ColumnName,
+			ColumnDataType: c.ColumnDataType,
+		}, true
+	case *LqAddDefaultValue:
+		return "dropDefaultValue", &LqDropDefaultValue{
+			TableName:      c.TableName,
+			ColumnName:     c.ColumnName,
+			ColumnDataType: c.ColumnDataType,
+		}, true
+	case *LqAddPrimaryKey:
+		return "dropPrimaryKey", &LqDropPrimaryKey{TableName: c.TableName}, true
+	case *LqAddUniqueConstraint:
+		return "dropUniqueConstraint", &LqDropUniqueConstraint{
+			TableName:      c.TableName,
+			ConstraintName: c.ConstraintName,
+		}, true
+	case *LqAddForeignKeyConstraint:
+		return "dropForeignKeyConstraint", &LqDropForeignKeyConstraint{
+			BaseTableName:  c.BaseTableName,
+			ConstraintName: c.ConstraintName,
+		}, true
+	case *LqCreateIndex:
+		return "dropIndex", &LqDropIndex{TableName: c.TableName, IndexName: c.IndexName}, true
+	}
+	return "", nil, false
+}
+
 func (s *LqChangeSet) CreateTable(table *LqCreateTable) {
 	s.Append("createTable", table)
 }
@@ -128,7 +214,16 @@ func newCreateTableChangeSet(
 		}
 		result = append(result, changeSet)
 	}
+	// Index
+	for _, index := range table.Indices {
+		changeSet := newLqChangeSet(id.bumpMinor(), author)
+		changeSet.Append("createIndex", newCreateIndex(table, index))
+		result = append(result, changeSet)
+	}
 
+	for _, changeSet := range result {
+		changeSet.tableName = table.Name
+	}
 	return result, nil
 }
 
@@ -208,8 +303,9 @@ func newAddColumn(table *Table, columns []*Column, useComments bool) (*LqAddColu
 }
 
 type LqDropColumn struct {
-	TableName  string `yaml:"tableName"`
-	ColumnName string `yaml:"columnName"`
+	TableName  string                 `yaml:"tableName"`
+	ColumnName string                 `yaml:"columnName,omitempty"`
+	Columns    []map[string]*LqColumn `yaml:"columns,omitempty"`
 }
 
 func newDropColumn(table *Table, columnName string) *LqDropColumn {
@@ -308,13 +404,14 @@ func newAddAutoIncrement(table *Table, column *Column) *LqAddAutoIncrement {
 }
 
 type LqAddDefaultValue struct {
-	TableName           string      `yaml:"tableName"`
-	ColumnName          string      `yaml:"columnName"`
-	ColumnDataType      string      `yaml:"columnDataType,omitempty"`
-	DefaultValue        string      `yaml:"defaultValue,omitempty"`
-	DefaultValueBoolean *bool       `yaml:"defaultValueBoolean,omitempty"`
-	DefaultValueNumeric interface{} `yaml:"defaultValueNumeric,omitempty"`
-	DefaultValueDate    string      `yaml:"defaultValueDate,omitempty"`
+	TableName            string      `yaml:"tableName"`
+	ColumnName           string      `yaml:"columnName"`
+	ColumnDataType       string      `yaml:"columnDataType,omitempty"`
+	DefaultValue         string      `yaml:"defaultValue,omitempty"`
+	DefaultValueBoolean  *bool       `yaml:"defaultValueBoolean,omitempty"`
+	DefaultValueNumeric  interface{} `yaml:"defaultValueNumeric,omitempty"`
+	DefaultValueDate     string      `yaml:"defaultValueDate,omitempty"`
+	DefaultValueComputed string      `yaml:"defaultValueComputed,omitempty"`
 }
 
 func newAddDefaultValue(table *Table, column *Column) (*LqAddDefaultValue, error) {
@@ -395,6 +492,82 @@ func newDropUniqueConstraint(table *Table, uniqueConstraintName string) *LqDropU
 	}
 }
 
+type LqAddForeignKeyConstraint struct {
+	BaseTableName         string `yaml:"baseTableName"`
+	BaseColumnNames       string `yaml:"baseColumnNames"`
+	ConstraintName        string `yaml:"constraintName"`
+	ReferencedTableName   string `yaml:"referencedTableName"`
+	ReferencedColumnNames string `yaml:"referencedColumnNames"`
+	OnDelete              string `yaml:"onDelete,omitempty"`
+	OnUpdate              string `yaml:"onUpdate,omitempty"`
+}
+
+// foreignKeyName returns foreign key constraint name of the column.
+func foreignKeyName(table *Table, column *Column) string {
+	return fmt.Sprintf("fk_%s_%s", table.Name, column.Name)
+}
+
+func newAddForeignKeyConstraint(table *Table, column *Column) *LqAddForeignKeyConstraint {
+	return &LqAddForeignKeyConstraint{
+		BaseTableName:         table.Name,
+		BaseColumnNames:       column.Name,
+		ConstraintName:        foreignKeyName(table, column),
+		ReferencedTableName:   column.Ref.Table,
+		ReferencedColumnNames: column.Ref.Column,
+	}
+}
+
+type LqDropForeignKeyConstraint struct {
+	BaseTableName  string `yaml:"baseTableName"`
+	ConstraintName string `yaml:"constraintName"`
+}
+
+func newDropForeignKeyConstraint(table *Table, column *Column) *LqDropForeignKeyConstraint {
+	return &LqDropForeignKeyConstraint{
+		BaseTableName:  table.Name,
+		ConstraintName: foreignKeyName(table, column),
+	}
+}
+
+type LqCreateIndex struct {
+	TableName string                 `yaml:"tableName"`
+	IndexName string                 `yaml:"indexName"`
+	Unique    *bool                  `yaml:"unique,omitempty"`
+	Columns   []map[string]*LqColumn `yaml:"columns"`
+}
+
+// indexName returns index name. generated from table and column names if not set.
+func indexName(table *Table, index *Index) string {
+	if index.Name != "" {
+		return index.Name
+	}
+	return fmt.Sprintf("idx_%s_%s", table.Name, strings.Join(index.Columns, "_"))
+}
+
+func newCreateIndex(table *Table, index *Index) *LqCreateIndex {
+	columns := make([]map[string]*LqColumn, 0)
+	for _, columnName := range index.Columns {
+		columns = append(columns, map[string]*LqColumn{"column": {Name: columnName}})
+	}
+	return &LqCreateIndex{
+		TableName: table.Name,
+		IndexName: indexName(table, index),
+		Columns:   columns,
+	}
+}
+
+type LqDropIndex struct {
+	TableName string `yaml:"tableName"`
+	IndexName string `yaml:"indexName"`
+}
+
+func newDropIndex(table *Table, index *Index) *LqDropIndex {
+	return &LqDropIndex{
+		TableName: table.Name,
+		IndexName: indexName(table, index),
+	}
+}
+
 // ----------------------------------------------------------------------------
 // Liquibase struct definitions
 // ----------------------------------------------------------------------------
@@ -437,16 +610,18 @@ func newLqDefaultValue(column *Column) (*LqDefaultValue, error) {
 }
 
 type LqColumn struct {
-	Name                string         `yaml:"name"`
-	Type                string         `yaml:"type"`
-	AutoIncrement       *bool          `yaml:"autoIncrement,omitempty"`
-	Constraints         *LqConstraints `yaml:"constraints,omitempty"`
-	Remarks             string         `yaml:"remarks,omitempty"`
-	DefaultValue        string         `yaml:"defaultValue,omitempty"`
-	DefaultValueBoolean *bool          `yaml:"defaultValueBoolean,omitempty"`
-	DefaultValueNumeric interface{}    `yaml:"defaultValueNumeric,omitempty"`
-	DefaultValueDate    string         `yaml:"defaultValueDate,omitempty"`
-	AfterColumn         string         `yaml:"afterColumn,omitempty"`
+	Name                 string         `yaml:"name"`
+	Type                 string         `yaml:"type,omitempty"`
+	AutoIncrement        *bool          `yaml:"autoIncrement,omitempty"`
+	Constraints          *LqConstraints `yaml:"constraints,omitempty"`
+	Remarks              string         `yaml:"remarks,omitempty"`
+	DefaultValue         string         `yaml:"defaultValue,omitempty"`
+	DefaultValueBoolean  *bool          `yaml:"defaultValueBoolean,omitempty"`
+	DefaultValueNumeric  interface{}    `yaml:"defaultValueNumeric,omitempty"`
+	DefaultValueDate     string         `yaml:"defaultValueDate,omitempty"`
+	DefaultValueComputed string         `yaml:"defaultValueComputed,omitempty"`
+	AfterColumn          string         `yaml:"afterColumn,omitempty"`
+	BeforeColumn         string         `yaml:"beforeColumn,omitempty"`
 }
 
 func newLqColumn(column *Column, createSeparatePK bool, createSeparateUq bool) (*LqColumn, error) {
@@ -494,12 +669,18 @@ func newLqColumn(column *Column, createSeparatePK bool, createSeparateUq bool) (
 }
 
 type LqConstraints struct {
-	PrimaryKey *bool `yaml:"primaryKey,omitempty"`
-	Nullable   *bool `yaml:"nullable,omitempty"`
-	Unique     *bool `yaml:"unique,omitempty"`
+	PrimaryKey            *bool  `yaml:"primaryKey,omitempty"`
+	Nullable              *bool  `yaml:"nullable,omitempty"`
+	Unique                *bool  `yaml:"unique,omitempty"`
+	UniqueConstraintName  string `yaml:"uniqueConstraintName,omitempty"`
+	ForeignKeyName        string `yaml:"foreignKeyName,omitempty"`
+	References            string `yaml:"references,omitempty"`
+	ReferencedTableName   string `yaml:"referencedTableName,omitempty"`
+	ReferencedColumnNames string `yaml:"referencedColumnNames,omitempty"`
 }
 
 type Liquibase struct {
+	schema *Schema
 }
 
 func (l *Liquibase) Generate(
@@ -507,13 +688,35 @@ func (l *Liquibase) Generate(
 	output *Output,
 	tableFilterFn TableFilterFn,
 ) error {
+	// output is a changelog file if it has changelog extension, otherwise a directory
+	outputDir := output.FilePath
+	outputFile := ""
+	format := output.Get(FlagChangelogFormat)
+	extFormat := getLqFormat(output.FilePath)
+	isOutputFile := extFormat != ""
+	if isOutputFile {
+		outputDir = path.Dir(output.FilePath)
+		outputFile = output.FilePath
+		if format == "" {
+			format = extFormat
+		}
+	}
+	if format == "" {
+		format = LqFormatYaml
+	}
+	if outputFile == "" {
+		outputFile = path.Join(outputDir,
+			fmt.Sprintf("%s-%s.%s", schema.Name, schema.Version, format))
+	}
+
 	// Create directory
-	if err := os.MkdirAll(output.FilePath, 0777); err != nil {
+	if err := os.MkdirAll(outputDir, 0777); err != nil {
 		return err
 	}
-	log.Printf("[MKDIR] %s", output.FilePath)
+	log.Printf("[MKDIR] %s", outputDir)
 
-	var outputBytes []byte
+	var changeLog *LqYaml
+	var oldSchema *Schema
 
 	diffFilename := output.Get(FlagDiff)
 	if diffFilename != "" {
@@ -524,31 +727,112 @@ func (l *Liquibase) Generate(
 			if targetSchema, err := input.ToSchema(); err != nil {
 				return err
 			} else {
-				if bytes, err := l.generateDiff(schema, targetSchema, output, tableFilterFn); err != nil {
+				oldSchema = targetSchema
+				if result, err := l.generateDiff(schema, targetSchema, output, tableFilterFn); err != nil {
 					return err
 				} else {
-					outputBytes = bytes
+					changeLog = result
 				}
 			}
 		}
 	} else {
 		// generate all
-		if bytes, err := l.generateAll(schema, output, tableFilterFn); err != nil {
+		if result, err := l.generateAll(schema, output, tableFilterFn); err != nil {
 			return err
 		} else {
-			outputBytes = bytes
+			changeLog = result
 		}
 	}
 
-	// Write file
-	outputFile := path.Join(output.FilePath,
-		fmt.Sprintf("%s-%s.yaml", schema.Name, schema.Version))
+	applyLqDbms(changeLog, output.Get(FlagDbms), output.Get(FlagTableOptions))
+
+	if err := assignLqChangeSetIds(changeLog, output.Get(FlagChangeSetId), schema.Version, time.Now()); err != nil {
+		return err
+	}
+
+	masterFile := output.Get(FlagMasterChangelog)
+	dbms := output.Get(FlagDbms)
+
+	split := output.Get(FlagSplit)
+	if split == "" {
+		// continue existing changelogs: new changelog file is included from the master changelog
+		if masterFile != "" {
+			if _, err := os.Stat(outputFile); err == nil {
+				return fmt.Errorf("changelog already exists: %s", outputFile)
+			}
+		}
+		if err := l.writeChangeLog(changeLog, format, outputFile, dbms, schema, oldSchema); err != nil {
+			return err
+		}
+		if masterFile != "" {
+			return appendLqIncludes(masterFile, []string{outputFile})
+		}
+		return nil
+	}
+
+	// split changelog per table or group
+	if isOutputFile {
+		return fmt.Errorf("output should be a directory to split changelog: %s", output.FilePath)
+	}
+	units, err := splitLqChangeLog(changeLog, split, schema, oldSchema)
+	if err != nil {
+		return err
+	}
+	files := make([]string, 0)
+	for _, unit := range units {
+		nameParts := make([]string, 0)
+		for _, part := range []string{schema.Name, schema.Version, unit.name} {
+			if part != "" {
+				nameParts = append(nameParts, part)
+			}
+		}
+		file := path.Join(outputDir, strings.Join(nameParts, "-")+"."+format)
+		if masterFile != "" {
+			if _, err := os.Stat(file); err == nil {
+				return fmt.Errorf("changelog already exists: %s", file)
+			}
+		}
+		if err := l.writeChangeLog(unit.changeLog, format, file, dbms, schema, oldSchema); err != nil {
+			return err
+		}
+		files = append(files, file)
+	}
+	if masterFile != "" {
+		return appendLqIncludes(masterFile, files)
+	}
+	return writeLqMasterChangeLog(path.Join(outputDir, lqMasterChangeLogFilename), files)
+}
 
+// writeChangeLog writes changelog file in the given format.
+func (l *Liquibase) writeChangeLog(
+	changeLog *LqYaml,
+	format string,
+	outputFile string,
+	dbms string,
+	schema *Schema,
+	oldSchema *Schema,
+) error {
+	var outputBytes []byte
+	if format == LqFormatSql {
+		writer, err := newLqSqlWriter(dbms, schema, oldSchema)
+		if err != nil {
+			return err
+		}
+		if outputBytes, err = writer.marshal(changeLog); err != nil {
+			return err
+		}
+	} else {
+		var err error
+		if outputBytes, err = marshalLqChangeLog(changeLog, format); err != nil {
+			return err
+		}
+	}
+
+	// Write file
 	if err := ioutil.WriteFile(outputFile, outputBytes, 0644); err != nil {
 		return err
 	}
 	log.Printf("[WRITE] %s", outputFile)
-
 	return nil
 }
 
@@ -556,18 +840,20 @@ func (l *Liquibase) generateAll(
 	schema *Schema,
 	output *Output,
 	tableFilterFn TableFilterFn,
-) ([]byte, error) {
+) (*LqYaml, error) {
 	result := newLqYaml()
 
 	uniqueNameSuffix := output.Get(FlagUniqueNameSuffix)
 	useComments := output.GetBool(FlagUseComments)
 
 	id := newLqId()
+	tables := make([]*Table, 0)
 	for _, table := range schema.Tables {
 		// filter table
 		if tableFilterFn != nil && !tableFilterFn(table) {
 			continue
 		}
+		tables = append(tables, table)
 
 		id.bumpMajor()
 
@@ -581,7 +867,37 @@ func (l *Liquibase) generateAll(
 		}
 	}
 
-	return yaml.Marshal(&result)
+	// foreign keys are added after all tables are created
+	tableNameSet := NewStringSet()
+	for _, table := range tables {
+		tableNameSet.Add(table.Name)
+	}
+	for _, table := range tables {
+		refColumns := make([]*Column, 0)
+		for _, column := range table.Columns {
+			if column.Ref == nil {
+				continue
+			}
+			if !tableNameSet.Contains(column.Ref.Table) {
+				log.Printf("foreign key is skipped. referenced table not found: %s.%s -> %s", table.Name, column.Name, column.Ref.Table)
+				continue
+			}
+			refColumns = append(refColumns, column)
+		}
+		if len(refColumns) == 0 {
+			continue
+		}
+
+		id.bumpMajor()
+		for _, column := range refColumns {
+			changeSet := newLqChangeSet(id.bumpMinor(), schema.Author)
+			changeSet.Append("addForeignKeyConstraint", newAddForeignKeyConstraint(table, column))
+			changeSet.tableName = table.Name
+			result.AddChangeSet(changeSet)
+		}
+	}
+
+	return result, nil
 }
 
 func (l *Liquibase) generateDiff(
@@ -589,7 +905,7 @@ func (l *Liquibase) generateDiff(
 	oldSchema *Schema,
 	output *Output,
 	tableFilterFn TableFilterFn,
-) ([]byte, error) {
+) (*LqYaml, error) {
 	result := newLqYaml()
 
 	uniqueNameSuffix := output.Get(FlagUniqueNameSuffix)
@@ -605,6 +921,35 @@ func (l *Liquibase) generateDiff(
 	}
 
 	id := newLqId()
+
+	// foreign keys are dropped before tables are changed, and added after all tables are changed.
+	foreignKeys := findLqForeignKeys(schema, tableFilterFn)
+	oldForeignKeys := findLqForeignKeys(oldSchema, tableFilterFn)
+	foreignKeyByName := make(map[string]*lqForeignKey)
+	for _, fk := range foreignKeys {
+		foreignKeyByName[fk.name] = fk
+	}
+	oldForeignKeyByName := make(map[string]*lqForeignKey)
+	for _, fk := range oldForeignKeys {
+		oldForeignKeyByName[fk.name] = fk
+	}
+
+	// drop removed or changed foreign keys
+	id.bumpMajor()
+	for _, oldFk := range oldForeignKeys {
+		if fk, ok := foreignKeyByName[oldFk.name]; ok && fk.equals(oldFk) {
+			continue
+		}
+		changeSet := newLqChangeSet(id.bumpMinor(), schema.Author)
+		changeSet.Append("dropForeignKeyConstraint", newDropForeignKeyConstraint(oldFk.table, oldFk.column))
+		changeSet.AppendRollback("addForeignKeyConstraint", newAddForeignKeyConstraint(oldFk.table, oldFk.column))
+		changeSet.tableName = oldFk.table.Name
+		result.AddChangeSet(changeSet)
+	}
+	if id.minor == 0 {
+		id.revertMajor()
+	}
+
 	for _, table := range schema.Tables {
 		// filter table
 		if tableFilterFn != nil && !tableFilterFn(table) {
@@ -627,6 +972,7 @@ func (l *Liquibase) generateDiff(
 			return nil, err
 		} else if len(diffChangeSet) > 0 {
 			for _, changeSet := range diffChangeSet {
+				changeSet.tableName = tableName
 				result.AddChangeSet(changeSet)
 			}
 		} else {
@@ -654,6 +1000,16 @@ func (l *Liquibase) generateDiff(
 		// drop table
 		changeSet := newLqChangeSet(id.version(), schema.Author)
 		changeSet.Append("dropTable", &LqDropTable{TableName: tableName})
+		changeSet.tableName = tableName
+
+		// rollback: create old table
+		if oldChangeSets, err := newCreateTableChangeSet(newLqId(), schema.Author, oldTableByName[tableName], uniqueNameSuffix, useComments); err != nil {
+			return nil, err
+		} else {
+			for _, oldChangeSet := range oldChangeSets {
+				changeSet.Rollback = append(changeSet.Rollback, oldChangeSet.Changes...)
+			}
+		}
 		result.AddChangeSet(changeSet)
 	}
 
@@ -662,9 +1018,11 @@ func (l *Liquibase) generateDiff(
 		id.bumpMajor()
 
 		// drop old unique constraint
-		if oldTable.UniqueKeyNameSet().Size() > 0 {
+		if oldUqSet := oldTable.UniqueKeyNameSet(); oldUqSet.Size() > 0 {
 			changeSet := newLqChangeSet(id.version(), schema.Author)
 			changeSet.Append("dropUniqueConstraint", newDropUniqueConstraint(oldTable, oldTable.Name+uniqueNameSuffix))
+			changeSet.AppendRollback("addUniqueConstraint", newAddUniqueConstraint(oldTable, oldUqSet.Join(", "), oldTable.Name+uniqueNameSuffix))
+			changeSet.tableName = newTable.Name
 			result.AddChangeSet(changeSet)
 		}
 
@@ -672,6 +1030,8 @@ func (l *Liquibase) generateDiff(
 		{
 			changeSet := newLqChangeSet(id.bumpMinor(), schema.Author)
 			changeSet.Append("renameTable", newRenameTable(newTable.Name, oldTable.Name))
+			changeSet.deriveRollback()
+			changeSet.tableName = newTable.Name
 			result.AddChangeSet(changeSet)
 		}
 
@@ -683,6 +1043,8 @@ func (l *Liquibase) generateDiff(
 
 			changeSet := newLqChangeSet(id.bumpMinor(), schema.Author)
 			changeSet.Append("addUniqueConstraint", newAddUniqueConstraint(newTable, uniqueColumeNames, uniqueConstraintName))
+			changeSet.deriveRollback()
+			changeSet.tableName = newTable.Name
 			result.AddChangeSet(changeSet)
 		}
 	}
@@ -701,12 +1063,64 @@ func (l *Liquibase) generateDiff(
 			return nil, err
 		} else {
 			for _, changeSet := range changeSets {
+				changeSet.deriveRollback()
 				result.AddChangeSet(changeSet)
 			}
 		}
 	}
 
-	return yaml.Marshal(&result)
+	// add new or changed foreign keys
+	id.bumpMajor()
+	for _, fk := range foreignKeys {
+		if oldFk, ok := oldForeignKeyByName[fk.name]; ok && fk.equals(oldFk) {
+			continue
+		}
+		changeSet := newLqChangeSet(id.bumpMinor(), schema.Author)
+		changeSet.Append("addForeignKeyConstraint", newAddForeignKeyConstraint(fk.table, fk.column))
+		changeSet.deriveRollback()
+		changeSet.tableName = fk.table.Name
+		result.AddChangeSet(changeSet)
+	}
+
+	return result, nil
+}
+
+type lqForeignKey struct {
+	name   string
+	table  *Table
+	column *Column
+}
+
+func (fk *lqForeignKey) equals(other *lqForeignKey) bool {
+	return fk.name == other.name &&
+		fk.column.Ref.Table == other.column.Ref.Table &&
+		fk.column.Ref.Column == other.column.Ref.Column
+}
+
+// findLqForeignKeys returns foreign keys of filtered tables which reference existing tables.
+func findLqForeignKeys(schema *Schema, tableFilterFn TableFilterFn) []*lqForeignKey {
+	tableByName := schema.TableByName()
+
+	result := make([]*lqForeignKey, 0)
+	for _, table := range schema.Tables {
+		if tableFilterFn != nil && !tableFilterFn(table) {
+			continue
+		}
+		for _, column := range table.Columns {
+			if column.Ref == nil {
+				continue
+			}
+			if _, ok := tableByName[column.Ref.Table]; !ok {
+				continue
+			}
+			result = append(result, &lqForeignKey{
+				name:   foreignKeyName(table, column),
+				table:  table,
+				column: column,
+			})
+		}
+	}
+	return result
 }
 
 // diffTable compares two tables.
@@ -723,9 +1137,28 @@ func (l *Liquibase) diffTable(
 	if useComments && table.Description != oldTable.Description {
 		changeSet := newLqChangeSet(id.bumpMinor(), author)
 		changeSet.Append("setTableRemarks", newSetTableRemarks(table))
+		changeSet.AppendRollback("setTableRemarks", newSetTableRemarks(&Table{Name: table.Name, Description: oldTable.Description}))
 		changeSets = append(changeSets, changeSet)
 	}
 
+	// drop removed or changed indices before columns are changed
+	indexByName := make(map[string]*Index)
+	for _, index := range table.Indices {
+		indexByName[indexName(table, index)] = index
+	}
+	oldIndexByName := make(map[string]*Index)
+	for _, oldIndex := range oldTable.Indices {
+		name := indexName(oldTable, oldIndex)
+		oldIndexByName[name] = oldIndex
+
+		if index, ok := indexByName[name]; !ok || !cmp.Equal(index.Columns, oldIndex.Columns) {
+			changeSet := newLqChangeSet(id.bumpMinor(), author)
+			changeSet.Append("dropIndex", newDropIndex(oldTable, oldIndex))
+			changeSet.AppendRollback("createIndex", newCreateIndex(oldTable, oldIndex))
+			changeSets = append(changeSets, changeSet)
+		}
+	}
+
 	oldColumnByName := oldTable.ColumnByName()
 
 	addedColumns := make([]*Column, 0)
@@ -777,6 +1210,9 @@ func (l *Liquibase) diffTable(
 		uniqueConstraintName := table.Name + uniqueNameSuffix
 		changeSet := newLqChangeSet(id.bumpMinor(), author)
 		changeSet.Append("dropUniqueConstraint", newDropUniqueConstraint(table, uniqueConstraintName))
+		if oldUqSet.Size() > 0 {
+			changeSet.AppendRollback("addUniqueConstraint", newAddUniqueConstraint(table, oldUqSet.Join(", "), uniqueConstraintName))
+		}
 		changeSets = append(changeSets, changeSet)
 	}
 
@@ -784,6 +1220,13 @@ func (l *Liquibase) diffTable(
 	for _, columnName := range removedColumnNameSet.Slice() {
 		changeSet := newLqChangeSet(id.bumpMinor(), author)
 		changeSet.Append("dropColumn", newDropColumn(table, columnName))
+
+		// rollback: add old column
+		if lqAddColumn, err := newAddColumn(oldTable, []*Column{oldColumnByName[columnName]}, useComments); err != nil {
+			return nil, err
+		} else {
+			changeSet.AppendRollback("addColumn", lqAddColumn)
+		}
 		changeSets = append(changeSets, changeSet)
 	}
 
@@ -791,12 +1234,17 @@ func (l *Liquibase) diffTable(
 	for newColumn, oldColumn := range renamedColumnMap {
 		changeSet := newLqChangeSet(id.bumpMinor(), author)
 		changeSet.Append("renameColumn", newRenameColumn(table, newColumn, oldColumn))
+		changeSet.AppendRollback("renameColumn", newRenameColumn(table, oldColumn, newColumn))
+		if !oldColumn.Nullable {
+			changeSet.AppendRollback("addNotNullConstraint", newAddNotNullConstraint(table, oldColumn))
+		}
 		changeSets = append(changeSets, changeSet)
 
 		// not null constraint is removed after renameColumn. (fixed in liquibase v4.0)
 		if !oldColumn.Nullable {
 			changeSet = newLqChangeSet(id.bumpMinor(), author)
 			changeSet.Append("addNotNullConstraint", newAddNotNullConstraint(table, newColumn))
+			changeSet.deriveRollback()
 			changeSets = append(changeSets, changeSet)
 		}
 	}
@@ -816,6 +1264,7 @@ func (l *Liquibase) diffTable(
 			return nil, err
 		} else {
 			changeSet.Append("addColumn", lqAddColumn)
+			changeSet.deriveRollback()
 			changeSets = append(changeSets, changeSet)
 		}
 	}
@@ -831,6 +1280,10 @@ func (l *Liquibase) diffTable(
 		if pkSet.Size() > 0 {
 			pkColumeNames := pkSet.Join(", ")
 			changeSet.Append("addPrimaryKey", newAddPrimaryKey(table, pkColumeNames))
+			changeSet.AppendRollback("dropPrimaryKey", newDropPrimaryKey(table))
+		}
+		if oldPkSet.Size() > 0 {
+			changeSet.AppendRollback("addPrimaryKey", newAddPrimaryKey(table, oldPkSet.Join(", ")))
 		}
 		changeSets = append(changeSets, changeSet)
 	}
@@ -842,10 +1295,21 @@ func (l *Liquibase) diffTable(
 		if uqSet.Size() > 0 {
 			uniqueColumeNames := uqSet.Join(", ")
 			changeSet.Append("addUniqueConstraint", newAddUniqueConstraint(table, uniqueColumeNames, uniqueConstraintName))
+			changeSet.deriveRollback()
 		}
 		changeSets = append(changeSets, changeSet)
 	}
 
+	// create added or changed indices
+	for _, index := range table.Indices {
+		if oldIndex, ok := oldIndexByName[indexName(table, index)]; !ok || !cmp.Equal(index.Columns, oldIndex.Columns) {
+			changeSet := newLqChangeSet(id.bumpMinor(), author)
+			changeSet.Append("createIndex", newCreateIndex(table, index))
+			changeSet.deriveRollback()
+			changeSets = append(changeSets, changeSet)
+		}
+	}
+
 	return changeSets, nil
 }
 
@@ -863,18 +1327,21 @@ func (l *Liquibase) diffColumn(
 	if column.Name != oldColumn.Name {
 		changeSet := newLqChangeSet(id.bumpMinor(), author)
 		changeSet.Append("renameColumn", newRenameColumn(table, column, oldColumn))
+		changeSet.AppendRollback("renameColumn", newRenameColumn(table, oldColumn, column))
 		changeSets = append(changeSets, changeSet)
 	}
 
 	if column.Type != oldColumn.Type || column.Size != oldColumn.Size || column.Scale != oldColumn.Scale {
 		changeSet := newLqChangeSet(id.bumpMinor(), author)
 		changeSet.Append("modifyDataType", newModifyDataType(table, column))
+		changeSet.AppendRollback("modifyDataType", newModifyDataType(table, oldColumn))
 		changeSets = append(changeSets, changeSet)
 	}
 
 	if useComments && column.Description != oldColumn.Description {
 		changeSet := newLqChangeSet(id.bumpMinor(), author)
 		changeSet.Append("setColumnRemarks", newSetColumnRemarks(table, column))
+		changeSet.AppendRollback("setColumnRemarks", newSetColumnRemarks(table, oldColumn))
 		changeSets = append(changeSets, changeSet)
 	}
 
@@ -882,8 +1349,10 @@ func (l *Liquibase) diffColumn(
 		changeSet := newLqChangeSet(id.bumpMinor(), author)
 		if column.Nullable {
 			changeSet.Append("dropNotNullConstraint", newDropNotNullConstraint(table, column))
+			changeSet.AppendRollback("addNotNullConstraint", newAddNotNullConstraint(table, oldColumn))
 		} else {
 			changeSet.Append("addNotNullConstraint", newAddNotNullConstraint(table, column))
+			changeSet.AppendRollback("dropNotNullConstraint", newDropNotNullConstraint(table, oldColumn))
 		}
 		changeSets = append(changeSets, changeSet)
 	}
@@ -892,8 +1361,10 @@ func (l *Liquibase) diffColumn(
 		if !column.AutoIncremental {
 			log.Printf("liquibase does not support drop autoIncrement. column: %v", column)
 		} else {
+			// rollback: restoring old data type removes autoIncrement
 			changeSet := newLqChangeSet(id.bumpMinor(), author)
 			changeSet.Append("addAutoIncrement", newAddAutoIncrement(table, column))
+			changeSet.AppendRollback("modifyDataType", newModifyDataType(table, oldColumn))
 			changeSets = append(changeSets, changeSet)
 		}
 	}
@@ -909,6 +1380,17 @@ func (l *Liquibase) diffColumn(
 				changeSet.Append("addDefaultValue", change)
 			}
 		}
+
+		// rollback: restore old default value
+		if oldColumn.DefaultValue == "" {
+			changeSet.AppendRollback("dropDefaultValue", newDropDefaultValue(table, oldColumn))
+		} else {
+			if change, err := newAddDefaultValue(table, oldColumn); err != nil {
+				return nil, err
+			} else {
+				changeSet.AppendRollback("addDefaultValue", change)
+			}
+		}
 		changeSets = append(changeSets, changeSet)
 	}
 
@@ -990,3 +1472,68 @@ func (l *LqId) version() string {
 		return fmt.Sprintf("%d-%d", l.major, l.minor)
 	}
 }
+
+// changeSet ID strategies
+const (
+	LqIdSequence  = "sequence"
+	LqIdVersion   = "version"
+	LqIdTimestamp = "timestamp"
+	LqIdHash      = "hash"
+)
+
+// assignLqChangeSetIds replaces sequential changeSet IDs according to the strategy.
+//   - sequence: 1, 1-1, 2, ... (default)
+//   - version: <schema version>-<n>. e.g. 2.3.0-5
+//   - timestamp: <yyyyMMddHHmmss>-<n>
+//   - hash: hash of author and changes. stable regardless of table order.
+func assignLqChangeSetIds(changeLog *LqYaml, strategy string, version string, now time.Time) error {
+	var prefix string
+	switch strategy {
+	case "", LqIdSequence:
+		return nil
+	case LqIdVersion:
+		if version == "" {
+			return errors.New("schema version is not set for changeSet ID strategy: version")
+		}
+		prefix = version
+	case LqIdTimestamp:
+		prefix = now.Format("20060102150405")
+	case LqIdHash:
+	default:
+		return fmt.Errorf("unsupported changeSet ID strategy: %s", strategy)
+	}
+
+	idSet := NewStringSet()
+	n := 0
+	for _, entry := range changeLog.DatabaseChangeLog {
+		entryMap, ok := entry.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		changeSet, ok := entryMap["changeSet"].(*LqChangeSet)
+		if !ok {
+			continue
+		}
+
+		n++
+		var id string
+		if strategy == LqIdHash {
+			data, err := yaml.Marshal(changeSet.Changes)
+			if err != nil {
+				return err
+			}
+			sum := sha1.Sum(append([]byte(changeSet.Author+"\n"), data...))
+			id = hex.EncodeToString(sum[:])[:12]
+
+			// identical changes in a changelog get suffixes
+			for i := 2; idSet.Contains(id); i++ {
+				id = fmt.Sprintf("%s-%d", hex.EncodeToString(sum[:])[:12], i)
+			}
+		} else {
+			id = fmt.Sprintf("%s-%d", prefix, n)
+		}
+		idSet.Add(id)
+		changeSet.Id = id
+	}
+	return nil
+}
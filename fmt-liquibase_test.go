@@ -0,0 +1,825 @@
+package main
+
+import (
+	"fmt"
+	"github.com/google/go-cmp/cmp"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLiquibase_FromFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "liquibase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string][]string{
+		"master.yaml": {
+			"databaseChangeLog:",
+			"  - include:",
+			"      file: changes/1.yaml",
+			"  - includeAll:",
+			"      path: changes/more",
+		},
+		"changes/1.yaml": {
+			"databaseChangeLog:",
+			"  - changeSet:",
+			"      id: 1",
+			"      author: author",
+			"      changes:",
+			"        - createTable:",
+			"            tableName: group",
+			"            remarks: groups",
+			"            columns:",
+			"              - column: {name: id, type: bigint, autoIncrement: true, constraints: {primaryKey: true}}",
+			"              - column: {name: name, type: varchar(40), constraints: {nullable: false}}",
+			"        - createTable:",
+			"            tableName: user",
+			"            columns:",
+			"              - column: {name: id, type: bigint, constraints: {primaryKey: true}}",
+			"              - column: {name: score, type: 'decimal(10,2)', defaultValueNumeric: 1.5}",
+			"              - column: {name: active, type: boolean}",
+		},
+		"changes/more/2.yaml": {
+			"databaseChangeLog:",
+			"  - changeSet:",
+			"      id: 2",
+			"      author: author",
+			"      changes:",
+			"        - addColumn:",
+			"            tableName: user",
+			"            columns:",
+			"              - column: {name: group_id, type: bigint, afterColumn: id}",
+			"        - addUniqueConstraint: {tableName: group, columnNames: name, constraintName: group_UNIQUE}",
+			"        - addForeignKeyConstraint: {baseTableName: user, baseColumnNames: group_id, constraintName: fk_user_group, referencedTableName: group, referencedColumnNames: id}",
+			"        - renameColumn: {tableName: user, oldColumnName: score, newColumnName: point, columnDataType: 'decimal(10,2)'}",
+			"        - modifyDataType: {tableName: user, columnName: point, newDataType: int}",
+			"        - dropColumn: {tableName: user, columnName: active}",
+		},
+	}
+	for filename, lines := range files {
+		filename = filepath.Join(dir, filename)
+		if err := os.MkdirAll(filepath.Dir(filename), 0777); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filename, []byte(strings.Join(lines, "\n")), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	liquibase := Liquibase{}
+	if err := liquibase.FromFile(filepath.Join(dir, "master.yaml")); err != nil {
+		t.Fatal(err)
+	}
+	schema, err := liquibase.ToSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &Schema{
+		Author: "author",
+		Tables: []*Table{
+			{
+				Name: "group",
+				Columns: []*Column{
+					{
+						Name:            "id",
+						Type:            ColTypeLong,
+						PrimaryKey:      true,
+						AutoIncremental: true,
+					},
+					{
+						Name:      "name",
+						Type:      ColTypeString,
+						Size:      40,
+						UniqueKey: true,
+					},
+				},
+				Description: "groups",
+			},
+			{
+				Name: "user",
+				Columns: []*Column{
+					{
+						Name:       "id",
+						Type:       ColTypeLong,
+						PrimaryKey: true,
+					},
+					{
+						Name:     "group_id",
+						Type:     ColTypeLong,
+						Nullable: true,
+						Ref:      &Reference{Table: "group", Column: "id"},
+					},
+					{
+						Name:         "point",
+						Type:         ColTypeInt,
+						Nullable:     true,
+						DefaultValue: "1.5",
+					},
+				},
+			},
+		},
+	}
+
+	if diff := cmp.Diff(expected, schema); diff != "" {
+		t.Errorf("TestLiquibase_FromFile() mismatch (-expected +actual):\n%s", diff)
+	}
+}
+
+func TestMarshalLqChangeLog(t *testing.T) {
+	changeLog := newLqYaml()
+	changeSet := newLqChangeSet("1", "author")
+	createTable := &LqCreateTable{
+		TableName: "user",
+		Remarks:   "<users>",
+		Columns:   make([]map[string]*LqColumn, 0),
+	}
+	createTable.AddColumn(&LqColumn{
+		Name:          "id",
+		Type:          "bigint",
+		AutoIncrement: NewBool(true),
+		Constraints:   &LqConstraints{PrimaryKey: NewBool(true)},
+	})
+	changeSet.CreateTable(createTable)
+	changeLog.AddChangeSet(changeSet)
+
+	expectedXml := []string{
+		`<?xml version="1.0" encoding="UTF-8"?>`,
+		`<databaseChangeLog xmlns="http://www.liquibase.org/xml/ns/dbchangelog"`,
+		`xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance"`,
+		`xsi:schemaLocation="` + lqXmlSchemaLocation + `"`,
+		`objectQuotingStrategy="QUOTE_ALL_OBJECTS">`,
+		`<changeSet id="1" author="author">`,
+		`<createTable tableName="user" remarks="&lt;users&gt;">`,
+		`<column name="id" type="bigint" autoIncrement="true">`,
+		`<constraints primaryKey="true"/>`,
+		`</column>`,
+		`</createTable>`,
+		`</changeSet>`,
+		`</databaseChangeLog>`,
+		``,
+	}
+	expectedJson := []string{
+		`{`,
+		`"databaseChangeLog": [`,
+		`{`,
+		`"objectQuotingStrategy": "QUOTE_ALL_OBJECTS"`,
+		`},`,
+		`{`,
+		`"changeSet": {`,
+		`"id": "1",`,
+		`"author": "author",`,
+		`"changes": [`,
+		`{`,
+		`"createTable": {`,
+		`"tableName": "user",`,
+		`"remarks": "<users>",`,
+		`"columns": [`,
+		`{`,
+		`"column": {`,
+		`"name": "id",`,
+		`"type": "bigint",`,
+		`"autoIncrement": true,`,
+		`"constraints": {`,
+		`"primaryKey": true`,
+		`}`, `}`, `}`, `]`, `}`, `}`, `]`, `}`, `}`, `]`, `}`,
+		``,
+	}
+
+	for format, expected := range map[string][]string{LqFormatXml: expectedXml, LqFormatJson: expectedJson} {
+		data, err := marshalLqChangeLog(changeLog, format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		actual := make([]string, 0)
+		for _, line := range strings.Split(string(data), "\n") {
+			actual = append(actual, strings.TrimSpace(line))
+		}
+		if diff := cmp.Diff(expected, actual); diff != "" {
+			t.Errorf("TestMarshalLqChangeLog(%s) mismatch (-expected +actual):\n%s", format, diff)
+		}
+	}
+}
+
+func TestLqSqlWriter_Marshal(t *testing.T) {
+	changeLog := newLqYaml()
+	changeSet := newLqChangeSet("1", "author")
+	createTable := &LqCreateTable{
+		TableName: "user",
+		Remarks:   "users",
+		Columns:   make([]map[string]*LqColumn, 0),
+	}
+	createTable.AddColumn(&LqColumn{
+		Name:          "id",
+		Type:          "bigint",
+		AutoIncrement: NewBool(true),
+		Constraints:   &LqConstraints{PrimaryKey: NewBool(true)},
+	})
+	createTable.AddColumn(&LqColumn{
+		Name:        "name",
+		Type:        "varchar(20)",
+		Constraints: &LqConstraints{Nullable: NewBool(false)},
+		Remarks:     "user's name",
+	})
+	changeSet.CreateTable(createTable)
+	changeLog.AddChangeSet(changeSet)
+
+	changeSet = newLqChangeSet("2", "author")
+	changeSet.Append("renameColumn", &LqRenameColumn{
+		TableName:      "user",
+		NewColumnName:  "nickname",
+		OldColumnName:  "name",
+		ColumnDataType: "varchar(20)",
+	})
+	changeLog.AddChangeSet(changeSet)
+
+	expected := map[string][]string{
+		DbmsMysql: {
+			"--liquibase formatted sql",
+			"",
+			"--changeset author:1",
+			"CREATE TABLE `user` (",
+			"  `id` bigint NOT NULL AUTO_INCREMENT,",
+			"  `name` varchar(20) NOT NULL COMMENT 'user''s name',",
+			"  PRIMARY KEY (`id`)",
+			") COMMENT = 'users';",
+			"--rollback DROP TABLE `user`;",
+			"",
+			"--changeset author:2",
+			"ALTER TABLE `user` CHANGE `name` `nickname` varchar(20);",
+			"--rollback ALTER TABLE `user` CHANGE `nickname` `name` varchar(20);",
+			"",
+		},
+		DbmsPostgresql: {
+			"--liquibase formatted sql",
+			"",
+			"--changeset author:1",
+			`CREATE TABLE "user" (`,
+			`  "id" bigint NOT NULL GENERATED BY DEFAULT AS IDENTITY,`,
+			`  "name" varchar(20) NOT NULL,`,
+			`  PRIMARY KEY ("id")`,
+			`);`,
+			`COMMENT ON TABLE "user" IS 'users';`,
+			`COMMENT ON COLUMN "user"."name" IS 'user''s name';`,
+			`--rollback DROP TABLE "user";`,
+			"",
+			"--changeset author:2",
+			`ALTER TABLE "user" RENAME COLUMN "name" TO "nickname";`,
+			`--rollback ALTER TABLE "user" RENAME COLUMN "nickname" TO "name";`,
+			"",
+		},
+	}
+
+	for dbms, expectedLines := range expected {
+		writer, err := newLqSqlWriter(dbms, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := writer.marshal(changeLog)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(expectedLines, strings.Split(string(data), "\n")); diff != "" {
+			t.Errorf("TestLqSqlWriter_Marshal(%s) mismatch (-expected +actual):\n%s", dbms, diff)
+		}
+	}
+}
+
+func TestLqSqlWriter_Marshal_PreConditions(t *testing.T) {
+	changeLog := newLqYaml()
+	changeSet := newLqChangeSet("1", "author")
+	changeSet.Append("dropTable", &LqDropTable{TableName: "tmp"})
+	changeLog.AddChangeSet(changeSet)
+
+	changeSet = newLqChangeSet("2", "author")
+	changeSet.Append("addColumn", &LqAddColumn{
+		TableName: "user",
+		Columns: []map[string]*LqColumn{
+			{"column": {Name: "email", Type: "varchar(100)"}},
+			{"column": {Name: "phone", Type: "varchar(20)"}},
+		},
+	})
+	changeLog.AddChangeSet(changeSet)
+
+	applyLqDbms(changeLog, DbmsPostgresql, "")
+
+	writer, err := newLqSqlWriter(DbmsPostgresql, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := writer.marshal(changeLog)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"--liquibase formatted sql",
+		"",
+		"--changeset author:1",
+		"--preconditions onFail:MARK_RAN",
+		"--precondition-sql-check expectedResult:1 SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = current_schema() AND table_name = 'tmp'",
+		`DROP TABLE "tmp";`,
+		"",
+		"--changeset author:2",
+		"--preconditions onFail:MARK_RAN",
+		"--precondition-sql-check expectedResult:0 SELECT COUNT(*) FROM information_schema.columns WHERE table_schema = current_schema() AND table_name = 'user' AND column_name = 'email'",
+		"--precondition-sql-check expectedResult:0 SELECT COUNT(*) FROM information_schema.columns WHERE table_schema = current_schema() AND table_name = 'user' AND column_name = 'phone'",
+		`ALTER TABLE "user" ADD COLUMN "email" varchar(100), ADD COLUMN "phone" varchar(20);`,
+		`--rollback ALTER TABLE "user" DROP COLUMN "email", DROP COLUMN "phone";`,
+		"",
+	}
+	if diff := cmp.Diff(expected, strings.Split(string(data), "\n")); diff != "" {
+		t.Errorf("TestLqSqlWriter_Marshal_PreConditions() mismatch (-expected +actual):\n%s", diff)
+	}
+}
+
+func TestLiquibase_GenerateDiff_Rollback(t *testing.T) {
+	oldSchema := &Schema{
+		Author: "author",
+		Tables: []*Table{
+			{
+				Name: "user",
+				Columns: []*Column{
+					{Name: "id", Type: ColTypeLong, PrimaryKey: true},
+					{Name: "name", Type: ColTypeString, Size: 20, DefaultValue: "foo", Nullable: true},
+					{Name: "age", Type: ColTypeInt},
+				},
+			},
+		},
+	}
+	schema := &Schema{
+		Author: "author",
+		Tables: []*Table{
+			{
+				Name: "user",
+				Columns: []*Column{
+					{Name: "id", Type: ColTypeLong, PrimaryKey: true},
+					{Name: "name", Type: ColTypeString, Size: 40, Nullable: true},
+				},
+			},
+		},
+	}
+
+	changeLog, err := (&Liquibase{}).generateDiff(schema, oldSchema, &Output{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// changes -> rollback changes
+	actual := make([]string, 0)
+	for _, entry := range changeLog.DatabaseChangeLog {
+		changeSet, ok := entry.(map[string]interface{})["changeSet"].(*LqChangeSet)
+		if !ok {
+			continue
+		}
+		keys := make([]string, 0)
+		for _, changes := range [][]map[string]interface{}{changeSet.Changes, changeSet.Rollback} {
+			for _, change := range changes {
+				for key, value := range change {
+					if c, ok := value.(*LqModifyDataType); ok {
+						key += "(" + c.NewDataType + ")"
+					}
+					keys = append(keys, key)
+				}
+			}
+		}
+		actual = append(actual, strings.Join(keys, " "))
+	}
+
+	expected := []string{
+		"modifyDataType(varchar(40)) modifyDataType(varchar(20))",
+		"dropDefaultValue addDefaultValue",
+		"dropColumn addColumn",
+	}
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Errorf("TestLiquibase_GenerateDiff_Rollback mismatch (-expected +actual):\n%s", diff)
+	}
+}
+
+func TestAssignLqChangeSetIds(t *testing.T) {
+	newChangeLog := func(tableNames ...string) *LqYaml {
+		changeLog := newLqYaml()
+		for i, tableName := range tableNames {
+			changeSet := newLqChangeSet(strconv.Itoa(i+1), "author")
+			changeSet.Append("dropTable", &LqDropTable{TableName: tableName})
+			changeLog.AddChangeSet(changeSet)
+		}
+		changeSet := newLqChangeSet("3", "author")
+		changeSet.Append("dropTable", &LqDropTable{TableName: tableNames[0]})
+		changeLog.AddChangeSet(changeSet)
+		return changeLog
+	}
+	ids := func(changeLog *LqYaml) []string {
+		result := make([]string, 0)
+		for _, entry := range changeLog.DatabaseChangeLog {
+			if changeSet, ok := entry.(map[string]interface{})["changeSet"].(*LqChangeSet); ok {
+				result = append(result, changeSet.Id)
+			}
+		}
+		return result
+	}
+
+	now := time.Date(2020, 5, 1, 12, 30, 0, 0, time.UTC)
+	changeLog := newChangeLog("user", "group")
+	if err := assignLqChangeSetIds(changeLog, LqIdVersion, "2.3.0", now); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"2.3.0-1", "2.3.0-2", "2.3.0-3"}, ids(changeLog)); diff != "" {
+		t.Errorf("TestAssignLqChangeSetIds(version) mismatch (-expected +actual):\n%s", diff)
+	}
+
+	changeLog = newChangeLog("user", "group")
+	if err := assignLqChangeSetIds(changeLog, LqIdTimestamp, "", now); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"20200501123000-1", "20200501123000-2", "20200501123000-3"}, ids(changeLog)); diff != "" {
+		t.Errorf("TestAssignLqChangeSetIds(timestamp) mismatch (-expected +actual):\n%s", diff)
+	}
+
+	// hash IDs do not depend on the order of tables
+	changeLog = newChangeLog("user", "group")
+	reordered := newChangeLog("group", "user")
+	if err := assignLqChangeSetIds(changeLog, LqIdHash, "", now); err != nil {
+		t.Fatal(err)
+	}
+	if err := assignLqChangeSetIds(reordered, LqIdHash, "", now); err != nil {
+		t.Fatal(err)
+	}
+	hashIds, reorderedIds := ids(changeLog), ids(reordered)
+	if hashIds[0] != reorderedIds[1] || hashIds[1] != reorderedIds[0] {
+		t.Errorf("TestAssignLqChangeSetIds(hash) IDs depend on order: %v, %v", hashIds, reorderedIds)
+	}
+	if hashIds[2] != hashIds[0]+"-2" {
+		t.Errorf("TestAssignLqChangeSetIds(hash) duplicated ID: %v", hashIds)
+	}
+}
+
+func TestSplitLqChangeLog(t *testing.T) {
+	schema := &Schema{
+		Author: "author",
+		Tables: []*Table{
+			{
+				Name:  "order",
+				Group: "shop",
+				Columns: []*Column{
+					{Name: "id", Type: ColTypeLong, PrimaryKey: true},
+					{Name: "user_id", Type: ColTypeLong, Ref: &Reference{Table: "user", Column: "id"}},
+				},
+			},
+			{
+				Name:  "user",
+				Group: "account",
+				Columns: []*Column{
+					{Name: "id", Type: ColTypeLong, PrimaryKey: true},
+				},
+			},
+			{
+				Name: "setting",
+				Columns: []*Column{
+					{Name: "id", Type: ColTypeLong, PrimaryKey: true},
+				},
+			},
+		},
+	}
+
+	changeLog, err := (&Liquibase{}).generateAll(schema, &Output{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for split, expected := range map[string][]string{
+		LqSplitTable: {"user: user(account)", "order: order(shop)", "setting: setting()", "foreign-keys: order(shop)"},
+		LqSplitGroup: {"account: user(account)", "shop: order(shop)", "default: setting()", "foreign-keys: order(shop)"},
+	} {
+		units, err := splitLqChangeLog(changeLog, split, schema, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		actual := make([]string, 0)
+		for _, unit := range units {
+			tables := make([]string, 0)
+			for _, entry := range unit.changeLog.DatabaseChangeLog {
+				if changeSet, ok := entry.(map[string]interface{})["changeSet"].(*LqChangeSet); ok {
+					tables = append(tables, fmt.Sprintf("%s(%s)", changeSet.tableName, changeSet.Labels))
+				}
+			}
+			actual = append(actual, unit.name+": "+strings.Join(tables, ", "))
+		}
+		if diff := cmp.Diff(expected, actual); diff != "" {
+			t.Errorf("TestSplitLqChangeLog(%s) mismatch (-expected +actual):\n%s", split, diff)
+		}
+	}
+}
+
+func TestSplitLqChangeLog_CircularReference(t *testing.T) {
+	oldSchema := &Schema{
+		Author: "author",
+		Tables: []*Table{
+			{Name: "item", Group: "shop", Columns: []*Column{{Name: "id", Type: ColTypeLong, PrimaryKey: true}}},
+			{Name: "user", Group: "account", Columns: []*Column{{Name: "id", Type: ColTypeLong, PrimaryKey: true}}},
+			{
+				Name:  "order",
+				Group: "shop",
+				Columns: []*Column{
+					{Name: "id", Type: ColTypeLong, PrimaryKey: true},
+					{Name: "user_id", Type: ColTypeLong, Ref: &Reference{Table: "user", Column: "id"}},
+				},
+			},
+			{
+				Name:  "wish",
+				Group: "account",
+				Columns: []*Column{
+					{Name: "id", Type: ColTypeLong, PrimaryKey: true},
+					{Name: "item_id", Type: ColTypeLong, Ref: &Reference{Table: "item", Column: "id"}},
+				},
+			},
+		},
+	}
+	// groups reference each other, and item referenced by wish is dropped
+	schema := &Schema{
+		Author: "author",
+		Tables: []*Table{
+			{
+				Name:  "user",
+				Group: "account",
+				Columns: []*Column{
+					{Name: "id", Type: ColTypeLong, PrimaryKey: true},
+					{Name: "last_order_id", Type: ColTypeLong, Ref: &Reference{Table: "order", Column: "id"}},
+				},
+			},
+			{
+				Name:  "order",
+				Group: "shop",
+				Columns: []*Column{
+					{Name: "id", Type: ColTypeLong, PrimaryKey: true},
+					{Name: "user_id", Type: ColTypeLong, Ref: &Reference{Table: "user", Column: "id"}},
+				},
+			},
+			{
+				Name:  "wish",
+				Group: "account",
+				Columns: []*Column{
+					{Name: "id", Type: ColTypeLong, PrimaryKey: true},
+					{Name: "item_id", Type: ColTypeLong},
+				},
+			},
+		},
+	}
+
+	splitChanges := func(changeLog *LqYaml, oldSchema *Schema) []string {
+		units, err := splitLqChangeLog(changeLog, LqSplitGroup, schema, oldSchema)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result := make([]string, 0)
+		for _, unit := range units {
+			for _, entry := range unit.changeLog.DatabaseChangeLog {
+				if changeSet, ok := entry.(map[string]interface{})["changeSet"].(*LqChangeSet); ok {
+					for _, change := range changeSet.Changes {
+						for key := range change {
+							result = append(result, unit.name+": "+changeSet.tableName+"."+key)
+						}
+					}
+				}
+			}
+		}
+		return result
+	}
+
+	// foreign keys are added after tables of all groups are created
+	changeLog, err := (&Liquibase{}).generateAll(schema, &Output{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		"account: user.createTable",
+		"account: wish.createTable",
+		"shop: order.createTable",
+		"foreign-keys: user.addForeignKeyConstraint",
+		"foreign-keys: order.addForeignKeyConstraint",
+	}
+	if diff := cmp.Diff(expected, splitChanges(changeLog, nil)); diff != "" {
+		t.Errorf("TestSplitLqChangeLog_CircularReference() mismatch (-expected +actual):\n%s", diff)
+	}
+
+	// foreign keys are dropped before tables of all groups are dropped
+	changeLog, err = (&Liquibase{}).generateDiff(schema, oldSchema, &Output{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = []string{
+		"drop-foreign-keys: wish.dropForeignKeyConstraint",
+		"shop: item.dropTable",
+		"account: user.addColumn",
+		"foreign-keys: user.addForeignKeyConstraint",
+	}
+	if diff := cmp.Diff(expected, splitChanges(changeLog, oldSchema)); diff != "" {
+		t.Errorf("TestSplitLqChangeLog_CircularReference() diff mismatch (-expected +actual):\n%s", diff)
+	}
+}
+
+func TestLiquibase_GenerateDiff_ForeignKeys(t *testing.T) {
+	oldSchema := &Schema{
+		Author: "author",
+		Tables: []*Table{
+			{
+				Name: "user",
+				Columns: []*Column{
+					{Name: "id", Type: ColTypeLong, PrimaryKey: true},
+					{Name: "team_id", Type: ColTypeLong, Ref: &Reference{Table: "team", Column: "id"}},
+				},
+			},
+			{
+				Name: "team",
+				Columns: []*Column{
+					{Name: "id", Type: ColTypeLong, PrimaryKey: true},
+				},
+			},
+		},
+	}
+	schema := &Schema{
+		Author: "author",
+		Tables: []*Table{
+			{
+				Name: "user",
+				Columns: []*Column{
+					{Name: "id", Type: ColTypeLong, PrimaryKey: true},
+					{Name: "team_id", Type: ColTypeLong},
+					{Name: "group_id", Type: ColTypeLong, Ref: &Reference{Table: "group", Column: "id"}},
+				},
+				Indices: []*Index{{Name: "idx_user_group", Columns: []string{"group_id"}}},
+			},
+			{
+				Name: "group",
+				Columns: []*Column{
+					{Name: "id", Type: ColTypeLong, PrimaryKey: true},
+					{Name: "name", Type: ColTypeString, Size: 20},
+				},
+			},
+		},
+	}
+
+	changeLog, err := (&Liquibase{}).generateDiff(schema, oldSchema, &Output{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actual := make([]string, 0)
+	for _, entry := range changeLog.DatabaseChangeLog {
+		if changeSet, ok := entry.(map[string]interface{})["changeSet"].(*LqChangeSet); ok {
+			for _, change := range changeSet.Changes {
+				for key := range change {
+					actual = append(actual, changeSet.tableName+": "+key)
+				}
+			}
+		}
+	}
+
+	expected := []string{
+		"user: dropForeignKeyConstraint",
+		"user: addColumn",
+		"user: createIndex",
+		"team: dropTable",
+		"group: createTable",
+		"user: addForeignKeyConstraint",
+	}
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Errorf("TestLiquibase_GenerateDiff_ForeignKeys mismatch (-expected +actual):\n%s", diff)
+	}
+}
+
+func TestApplyLqDbms(t *testing.T) {
+	newChangeLog := func() *LqYaml {
+		changeLog := newLqYaml()
+		changeSet := newLqChangeSet("1", "author")
+		changeSet.CreateTable(&LqCreateTable{TableName: "user"})
+		changeLog.AddChangeSet(changeSet)
+
+		changeSet = newLqChangeSet("2", "author")
+		changeSet.Append("addAutoIncrement", &LqAddAutoIncrement{TableName: "user", ColumnName: "id"})
+		changeLog.AddChangeSet(changeSet)
+
+		changeSet = newLqChangeSet("3", "author")
+		changeSet.Append("dropColumn", &LqDropColumn{TableName: "user", ColumnName: "age"})
+		changeLog.AddChangeSet(changeSet)
+		return changeLog
+	}
+
+	toYaml := func(changeLog *LqYaml) []string {
+		data, err := marshalLqChangeLog(changeLog, LqFormatYaml)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return strings.Split(strings.TrimSpace(string(data)), "\n")
+	}
+
+	expected := map[string][]string{
+		"mysql": {
+			"databaseChangeLog:",
+			"- objectQuotingStrategy: QUOTE_ALL_OBJECTS",
+			"- changeSet:",
+			"    id: \"1\"",
+			"    author: author",
+			"    preConditions:",
+			"      not:",
+			"      - tableExists:",
+			"          tableName: user",
+			"      onFail: MARK_RAN",
+			"    changes:",
+			"    - createTable:",
+			"        tableName: user",
+			"        columns: []",
+			"    modifySql:",
+			"    - dbms: mysql",
+			"      append:",
+			"        value: ' ENGINE=InnoDB'",
+			"- changeSet:",
+			"    id: \"2\"",
+			"    author: author",
+			"    changes:",
+			"    - addAutoIncrement:",
+			"        tableName: user",
+			"        columnName: id",
+			"        columnDataType: \"\"",
+			"- changeSet:",
+			"    id: \"3\"",
+			"    author: author",
+			"    preConditions:",
+			"      columnExists:",
+			"        tableName: user",
+			"        columnName: age",
+			"      onFail: MARK_RAN",
+			"    changes:",
+			"    - dropColumn:",
+			"        tableName: user",
+			"        columnName: age",
+		},
+		"sqlite": {
+			"databaseChangeLog:",
+			"- objectQuotingStrategy: QUOTE_ALL_OBJECTS",
+			"- changeSet:",
+			"    id: \"1\"",
+			"    author: author",
+			"    preConditions:",
+			"      not:",
+			"      - tableExists:",
+			"          tableName: user",
+			"      onFail: MARK_RAN",
+			"    changes:",
+			"    - createTable:",
+			"        tableName: user",
+			"        columns: []",
+			"- changeSet:",
+			"    id: \"3\"",
+			"    author: author",
+			"    preConditions:",
+			"      columnExists:",
+			"        tableName: user",
+			"        columnName: age",
+			"      onFail: MARK_RAN",
+			"    changes:",
+			"    - dropColumn:",
+			"        tableName: user",
+			"        columnName: age",
+		},
+	}
+
+	for dbms, expectedLines := range expected {
+		changeLog := newChangeLog()
+		applyLqDbms(changeLog, dbms, "ENGINE=InnoDB")
+		if diff := cmp.Diff(expectedLines, toYaml(changeLog)); diff != "" {
+			t.Errorf("TestApplyLqDbms(%s) mismatch (-expected +actual):\n%s", dbms, diff)
+		}
+	}
+}
+
+func TestAddLqExistsPreConditions(t *testing.T) {
+	changeSet := newLqChangeSet("1", "author")
+	changeSet.CreateTable(&LqCreateTable{TableName: "user"})
+	changeSet.Append("addAutoIncrement", &LqAddAutoIncrement{TableName: "user", ColumnName: "id"})
+	changeSet.Append("renameColumn", &LqRenameColumn{TableName: "group", OldColumnName: "title", NewColumnName: "name"})
+	addLqExistsPreConditions(changeSet)
+
+	expected := map[string]interface{}{
+		"onFail": "MARK_RAN",
+		"and": []map[string]interface{}{
+			{
+				"not": []map[string]interface{}{
+					{"tableExists": &LqTableExists{TableName: "user"}},
+				},
+			},
+			{"columnExists": &LqColumnExists{TableName: "group", ColumnName: "title"}},
+		},
+	}
+	if diff := cmp.Diff(expected, changeSet.PreConditions); diff != "" {
+		t.Errorf("TestAddLqExistsPreConditions() mismatch (-expected +actual):\n%s", diff)
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strings"
+)
+
+type Mermaid struct {
+	TableFilterFn TableFilterFn
+}
+
+func (f *Mermaid) FromFile(filename string) error {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+
+	return f.FromString(data)
+}
+
+func (f *Mermaid) FromString(data []byte) error {
+	// TODO
+	return errors.New("not implemented")
+}
+
+func (f *Mermaid) ToSchema() (*Schema, error) {
+	// TODO
+	return nil, errors.New("not implemented")
+}
+
+func (f *Mermaid) ToFile(schema *Schema, filename string) error {
+	data, err := f.ToString(schema)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(filename, data, 0644)
+}
+
+func (f *Mermaid) ToString(schema *Schema) ([]byte, error) {
+	indent := "    "
+
+	tables := make([]*Table, 0)
+	tableNameSet := NewStringSet()
+	for _, table := range schema.Tables {
+		// filter table
+		if f.TableFilterFn != nil && !f.TableFilterFn(table) {
+			continue
+		}
+		tables = append(tables, table)
+		tableNameSet.Add(table.Name)
+	}
+
+	result := []string{"erDiagram"}
+	refs := make([]string, 0)
+	for _, table := range tables {
+		result = append(result, fmt.Sprintf("%s%s {", indent, f.quoteName(table.Name)))
+
+		for _, column := range table.Columns {
+			result = append(result, indent+indent+f.getAttributeDef(column))
+
+			// Reference
+			if ref := column.Ref; ref != nil && tableNameSet.Contains(ref.Table) {
+				refs = append(refs, indent+f.getRelationshipDef(table, column))
+			}
+		}
+		result = append(result, indent+"}")
+	}
+
+	result = append(result, refs...)
+	result = append(result, "")
+
+	return []byte(strings.Join(result, "\n")), nil
+}
+
+func (f *Mermaid) getAttributeDef(col *Column) string {
+	colType := col.Type
+	if col.Size > 0 && col.Scale == 0 {
+		colType = fmt.Sprintf("%s(%d)", colType, col.Size)
+	}
+
+	keys := make([]string, 0)
+	if col.PrimaryKey {
+		keys = append(keys, "PK")
+	}
+	if col.Ref != nil {
+		keys = append(keys, "FK")
+	}
+	if col.UniqueKey {
+		keys = append(keys, "UK")
+	}
+
+	line := fmt.Sprintf("%s %s", f.quoteName(colType), f.quoteName(col.Name))
+	if len(keys) > 0 {
+		line += " " + strings.Join(keys, ", ")
+	}
+	if col.Description != "" {
+		line += " " + Quote(mermaidCommentReplacer.Replace(col.Description), "\"")
+	}
+	return line
+}
+
+// getRelationshipDef returns relationship from referenced table to table.
+// Cardinality of referenced table is 'zero or one' if column is nullable, otherwise 'exactly one'.
+// Cardinality of table is 'zero or one' if column is unique, otherwise 'zero or more'.
+func (f *Mermaid) getRelationshipDef(table *Table, col *Column) string {
+	parentCardinality := TernaryString(col.Nullable, "|o", "||")
+	childCardinality := TernaryString(col.UniqueKey, "o|", "o{")
+
+	return fmt.Sprintf("%s %s--%s %s : %s",
+		f.quoteName(col.Ref.Table),
+		parentCardinality,
+		childCardinality,
+		f.quoteName(table.Name),
+		Quote(col.Name, "\""))
+}
+
+// mermaidCommentReplacer replaces characters not allowed in mermaid attribute comments.
+var mermaidCommentReplacer = strings.NewReplacer("\"", "'", "\r\n", " ", "\n", " ", "\r", " ")
+
+var mermaidInvalidNameRegexp = regexp.MustCompile(`[^A-Za-z0-9_\-()\[\]]`)
+
+// quoteName replaces characters not allowed in mermaid names.
+func (f *Mermaid) quoteName(name string) string {
+	return mermaidInvalidNameRegexp.ReplaceAllString(name, "_")
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"strings"
+	"testing"
+)
+
+func TestMermaid_ToString(t *testing.T) {
+	schema := &Schema{
+		Tables: []*Table{
+			{
+				Name:  "group",
+				Group: "common",
+				Columns: []*Column{
+					{Name: "id", Type: ColTypeLong, PrimaryKey: true},
+					{Name: "name", Type: ColTypeString, Size: 40, UniqueKey: true, Description: "group \"name\"\r\nsecond line"},
+				},
+			},
+			{
+				Name:  "user profile",
+				Group: "user",
+				Columns: []*Column{
+					{Name: "id", Type: ColTypeLong, PrimaryKey: true},
+					{Name: "group_id", Type: ColTypeLong, Nullable: true, Ref: &Reference{Table: "group", Column: "id"}},
+					{Name: "main_id", Type: ColTypeLong, UniqueKey: true, Ref: &Reference{Table: "group", Column: "id"}},
+					{Name: "point", Type: ColTypeDecimal, Size: 10, Scale: 2, Description: "line1\nline2"},
+				},
+			},
+		},
+	}
+
+	mermaid := Mermaid{}
+	data, err := mermaid.ToString(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := strings.Join([]string{
+		"erDiagram",
+		"    group {",
+		"        long id PK",
+		"        string(40) name UK \"group 'name' second line\"",
+		"    }",
+		"    user_profile {",
+		"        long id PK",
+		"        long group_id FK",
+		"        long main_id FK, UK",
+		"        decimal point \"line1 line2\"",
+		"    }",
+		"    group |o--o{ user_profile : \"group_id\"",
+		"    group ||--o| user_profile : \"main_id\"",
+		"",
+	}, "\n")
+	if diff := cmp.Diff(expected, string(data)); diff != "" {
+		t.Errorf("TestMermaid_ToString() mismatch (-expected +actual):\n%s", diff)
+	}
+}
+
+func TestMermaid_ToString_Groups(t *testing.T) {
+	schema := &Schema{
+		Tables: []*Table{
+			{
+				Name:    "group",
+				Group:   "common",
+				Columns: []*Column{{Name: "id", Type: ColTypeLong, PrimaryKey: true}},
+			},
+			{
+				Name:  "user",
+				Group: "user",
+				Columns: []*Column{
+					{Name: "id", Type: ColTypeLong, PrimaryKey: true},
+					{Name: "group_id", Type: ColTypeLong, Ref: &Reference{Table: "group", Column: "id"}},
+				},
+			},
+		},
+	}
+
+	mermaid := Mermaid{TableFilterFn: getTableFilterFn("user")}
+	data, err := mermaid.ToString(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// reference to filtered table is not written
+	expected := strings.Join([]string{
+		"erDiagram",
+		"    user {",
+		"        long id PK",
+		"        long group_id FK",
+		"    }",
+		"",
+	}, "\n")
+	if diff := cmp.Diff(expected, string(data)); diff != "" {
+		t.Errorf("TestMermaid_ToString_Groups() mismatch (-expected +actual):\n%s", diff)
+	}
+}
@@ -1,100 +1,81 @@
 package main
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"github.com/xwb1989/sqlparser"
 	"io"
 	"io/ioutil"
+	"log"
+	"os"
 	"strings"
 )
 
 type Mysql struct {
-	schema *Schema
+	schema   *Schema
+	Warnings []string
 }
 
 func (m *Mysql) FromFile(filename string) error {
-	data, err := ioutil.ReadFile(filename)
+	file, err := os.Open(filename)
 	if err != nil {
 		return err
 	}
+	defer file.Close()
 
-	return m.FromString(data)
+	return m.FromReader(file)
 }
 
 func (m *Mysql) FromString(data []byte) error {
-	tokens := sqlparser.NewStringTokenizer(string(data))
+	return m.FromReader(bytes.NewReader(data))
+}
 
-	tables := make([]*Table, 0)
-	for {
-		stmt, err := sqlparser.ParseNext(tokens)
-		if err == io.EOF {
-			break
+// FromReader reads statements one by one.
+// statements which cannot be applied to schema are skipped with warnings.
+func (m *Mysql) FromReader(reader io.Reader) error {
+	m.schema = nil
+	m.Warnings = make([]string, 0)
+
+	builder := newMysqlSchemaBuilder()
+	scanner := newMysqlStatementScanner(reader)
+	for scanner.Scan() {
+		stmt := scanner.Statement()
+		if stmt.Skipped {
+			continue
 		}
+
+		applied, err := builder.apply(stmt.Text)
 		if err != nil {
-			m.schema = nil
-			return err
+			m.warn(stmt, err.Error())
+			continue
 		}
-
-		switch stmt.(type) {
-		case *sqlparser.DDL:
-			ddl := stmt.(*sqlparser.DDL)
-			columns := make([]*Column, 0)
-
-			tableSpec := ddl.TableSpec
-
-			if tableSpec != nil {
-				pkSet := NewStringSet()
-				uniqueSet := NewStringSet()
-				for _, idx := range tableSpec.Indexes {
-					info := idx.Info
-					if info.Primary {
-						for _, c := range idx.Columns {
-							pkSet.Add(c.Column.String())
-						}
-					} else if info.Unique {
-						for _, c := range idx.Columns {
-							uniqueSet.Add(c.Column.String())
-						}
-					}
-				}
-
-				for _, col := range ddl.TableSpec.Columns {
-					name := col.Name.String()
-					nullable := !bool(col.Type.NotNull)
-					defaultValue := SQLValToString(col.Type.Default, "")
-					if nullable && defaultValue == "null" {
-						defaultValue = ""
-					}
-					comment := SQLValToString(col.Type.Comment, "")
-					columns = append(columns, &Column{
-						Name:            name,
-						Type:            m.fromColumnType(col.Type),
-						Description:     comment,
-						Size:            uint16(SQLValToInt(col.Type.Length, 0)),
-						Scale:           uint16(SQLValToInt(col.Type.Scale, 0)),
-						Nullable:        nullable,
-						PrimaryKey:      pkSet.Contains(name),
-						UniqueKey:       uniqueSet.Contains(name),
-						AutoIncremental: bool(col.Type.Autoincrement),
-						DefaultValue:    defaultValue,
-					})
-				}
-				tables = append(tables, &Table{
-					Name:    ddl.NewName.Name.String(),
-					Columns: columns,
-				})
-			}
+		if !applied {
+			m.warn(stmt, "unsupported statement")
 		}
 	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
 
-	m.schema = &Schema{
-		Tables: tables,
+	if len(m.Warnings) > 0 {
+		log.Printf("%d statements skipped", len(m.Warnings))
 	}
+	m.schema = builder.schema()
 
 	return nil
 }
 
+func (m *Mysql) warn(stmt *mysqlStatement, message string) {
+	summary := strings.Join(strings.Fields(stmt.Text), " ")
+	if len(summary) > 60 {
+		summary = summary[:60] + "..."
+	}
+	warning := fmt.Sprintf("statement #%d (line %d) skipped: %s. %s", stmt.Number, stmt.Line, message, summary)
+	m.Warnings = append(m.Warnings, warning)
+	log.Print(warning)
+}
+
 func (m *Mysql) ToSchema() (*Schema, error) {
 	if m.schema == nil {
 		return nil, errors.New("schema is not read")
@@ -171,6 +152,14 @@ func (m *Mysql) ToString(schema *Schema) ([]byte, error) {
 					m.quote(table.Name+"_UNIQUE"),
 					strings.Join(uniqueKeys, ", ")))
 		}
+		for _, index := range table.Indices {
+			columns := make([]string, 0, len(index.Columns))
+			for _, column := range index.Columns {
+				columns = append(columns, m.quote(column))
+			}
+			lines = append(lines,
+				fmt.Sprintf(indent+"KEY %s (%s)", m.quote(index.Name), strings.Join(columns, ", ")))
+		}
 		body := strings.Join(lines, ",\n")
 
 		tableDef := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (\n%s\n);", m.quote(table.Name), body)
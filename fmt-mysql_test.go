@@ -65,6 +65,250 @@ func TestMysql_ToSchema(t *testing.T) {
 	}
 }
 
+func TestMysql_ToSchema_Statements(t *testing.T) {
+	sql := strings.Join([]string{
+		"/*!40101 SET NAMES utf8mb4 */;",
+		"DROP TABLE IF EXISTS `group`;",
+		"CREATE TABLE `group` (",
+		"  `id` bigint NOT NULL AUTO_INCREMENT,",
+		"  `name` varchar(40) NOT NULL,",
+		"  PRIMARY KEY (`id`)",
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='user groups';",
+		"CREATE TABLE `user` (",
+		"  `id` bigint NOT NULL AUTO_INCREMENT,",
+		"  `group_id` bigint DEFAULT NULL,",
+		"  PRIMARY KEY (`id`),",
+		"  KEY `fk_group` (`group_id`),",
+		"  CONSTRAINT `fk_group` FOREIGN KEY (`group_id`) REFERENCES `group` (`id`) ON DELETE CASCADE",
+		") ENGINE=InnoDB;",
+		"CREATE TABLE post (id int PRIMARY KEY, user_id bigint REFERENCES user(id));",
+		"ALTER TABLE user ADD COLUMN email varchar(100) NOT NULL AFTER id, ADD INDEX idx_email (email);",
+		"ALTER TABLE post MODIFY user_id bigint NOT NULL;",
+		"CREATE UNIQUE INDEX ux_name ON `group` (name);",
+		"CREATE TABLE tmp (a int);",
+		"DROP TABLE tmp;",
+		"LOCK TABLES `user` WRITE;",
+		"INSERT INTO `user` VALUES (1,'a@b.c',NULL);",
+		"UNLOCK TABLES;",
+	}, "\n")
+
+	mysql := Mysql{}
+
+	// read sql
+	if err := mysql.FromString([]byte(sql)); err != nil {
+		t.Error(err)
+	}
+
+	// convert to schema
+	schema, err := mysql.ToSchema()
+	if err != nil {
+		t.Error(err)
+	}
+
+	expected := &Schema{
+		Tables: []*Table{
+			{
+				Name: "group",
+				Columns: []*Column{
+					{
+						Name:            "id",
+						Type:            ColTypeLong,
+						PrimaryKey:      true,
+						AutoIncremental: true,
+					},
+					{
+						Name:      "name",
+						Type:      ColTypeString,
+						Size:      40,
+						UniqueKey: true,
+					},
+				},
+				Description: "user groups",
+			},
+			{
+				Name: "user",
+				Columns: []*Column{
+					{
+						Name:            "id",
+						Type:            ColTypeLong,
+						PrimaryKey:      true,
+						AutoIncremental: true,
+					},
+					{
+						Name: "email",
+						Type: ColTypeString,
+						Size: 100,
+					},
+					{
+						Name:     "group_id",
+						Type:     ColTypeLong,
+						Nullable: true,
+						Ref:      &Reference{Table: "group", Column: "id"},
+					},
+				},
+				Indices: []*Index{
+					{Name: "fk_group", Columns: []string{"group_id"}},
+					{Name: "idx_email", Columns: []string{"email"}},
+				},
+			},
+			{
+				Name: "post",
+				Columns: []*Column{
+					{
+						Name:       "id",
+						Type:       ColTypeInt,
+						PrimaryKey: true,
+					},
+					{
+						Name: "user_id",
+						Type: ColTypeLong,
+						Ref:  &Reference{Table: "user", Column: "id"},
+					},
+				},
+			},
+		},
+	}
+
+	if diff := cmp.Diff(expected, schema); diff != "" {
+		t.Errorf("TestMysql_ToSchema_Statements() mismatch (-expected +actual):\n%s", diff)
+	}
+}
+
+func TestMysql_FromString_Dump(t *testing.T) {
+	sql := strings.Join([]string{
+		"-- MySQL dump",
+		"/*!40101 SET NAMES utf8mb4 */;",
+		"CREATE TABLE `user` (",
+		"  `id` bigint NOT NULL,",
+		"  `name` varchar(20) COMMENT 'it''s; name'",
+		");",
+		"LOCK TABLES `user` WRITE;",
+		"INSERT INTO `user` VALUES (1,'a;b'),(2,'c\\';d');",
+		"UNLOCK TABLES;",
+		"DELIMITER ;;",
+		"/*!50003 CREATE*/ /*!50003 TRIGGER `t1` BEFORE INSERT ON `user` FOR EACH ROW BEGIN",
+		"  SET NEW.name = 'x';",
+		"END */;;",
+		"DELIMITER ;",
+		"ALTER TABLE `user` ADD COLUMN age int;",
+	}, "\n")
+
+	mysql := Mysql{}
+	if err := mysql.FromString([]byte(sql)); err != nil {
+		t.Error(err)
+	}
+
+	expectedWarnings := []string{
+		"statement #6 (line 11) skipped: unsupported statement. CREATE TRIGGER `t1` BEFORE INSERT ON `user` FOR EACH ROW BEG...",
+	}
+	if diff := cmp.Diff(expectedWarnings, mysql.Warnings); diff != "" {
+		t.Errorf("TestMysql_FromString_Dump() warnings mismatch (-expected +actual):\n%s", diff)
+	}
+
+	schema, err := mysql.ToSchema()
+	if err != nil {
+		t.Error(err)
+	}
+	columnNames := make([]string, 0)
+	for _, column := range schema.Tables[0].Columns {
+		columnNames = append(columnNames, column.Name)
+	}
+	if diff := cmp.Diff([]string{"id", "name", "age"}, columnNames); diff != "" {
+		t.Errorf("TestMysql_FromString_Dump() mismatch (-expected +actual):\n%s", diff)
+	}
+	if desc := schema.Tables[0].Columns[1].Description; desc != "it's; name" {
+		t.Errorf("TestMysql_FromString_Dump() unexpected description: %s", desc)
+	}
+}
+
+func TestMysql_FromString_MalformedTable(t *testing.T) {
+	sql := strings.Join([]string{
+		"CREATE TABLE `user` (",
+		"  `id` bigint NOT NULL,",
+		"  `group_id` bigint,",
+		"  UNIQUE KEY `uq_group` (`group_id`),",
+		"  PRIMARY KEY (`uid`)",
+		");",
+		"CREATE TABLE `group` (",
+		"  `id` bigint NOT NULL,",
+		"  PRIMARY KEY (`id`)",
+		");",
+	}, "\n")
+
+	mysql := Mysql{}
+	if err := mysql.FromString([]byte(sql)); err != nil {
+		t.Error(err)
+	}
+	if len(mysql.Warnings) != 1 || !strings.Contains(mysql.Warnings[0], "column not found: uid") {
+		t.Errorf("TestMysql_FromString_MalformedTable() unexpected warnings: %v", mysql.Warnings)
+	}
+
+	schema, err := mysql.ToSchema()
+	if err != nil {
+		t.Error(err)
+	}
+	tableNames := make([]string, 0)
+	for _, table := range schema.Tables {
+		tableNames = append(tableNames, table.Name)
+	}
+	if diff := cmp.Diff([]string{"group"}, tableNames); diff != "" {
+		t.Errorf("TestMysql_FromString_MalformedTable() mismatch (-expected +actual):\n%s", diff)
+	}
+}
+
+func TestMysql_FromString_DropConstraint(t *testing.T) {
+	sql := strings.Join([]string{
+		"CREATE TABLE `group` (id bigint PRIMARY KEY, code varchar(10) UNIQUE KEY);",
+		"CREATE TABLE `user` (",
+		"  `id` bigint NOT NULL PRIMARY KEY,",
+		"  `group_id` bigint NOT NULL,",
+		"  `name` varchar(40) NOT NULL UNIQUE,",
+		"  CONSTRAINT `uq_group` UNIQUE (`group_id`),",
+		"  CONSTRAINT `fk_group` FOREIGN KEY (`group_id`) REFERENCES `group` (`id`)",
+		");",
+		"CREATE TABLE IF NOT EXISTS `user` (`id` bigint);",
+		"CREATE TABLE `user` (`id` bigint);",
+		"ALTER TABLE `user` DROP CONSTRAINT `fk_group`, DROP CONSTRAINT `uq_group`, DROP CONSTRAINT `chk_name`;",
+	}, "\n")
+
+	mysql := Mysql{}
+	if err := mysql.FromString([]byte(sql)); err != nil {
+		t.Error(err)
+	}
+	// duplicate table is warned only if 'IF NOT EXISTS' is not set
+	if len(mysql.Warnings) != 1 || !strings.Contains(mysql.Warnings[0], "table already exists: user") {
+		t.Errorf("TestMysql_FromString_DropConstraint() unexpected warnings: %v", mysql.Warnings)
+	}
+
+	schema, err := mysql.ToSchema()
+	if err != nil {
+		t.Error(err)
+	}
+
+	expected := &Schema{
+		Tables: []*Table{
+			{
+				Name: "group",
+				Columns: []*Column{
+					{Name: "id", Type: ColTypeLong, PrimaryKey: true},
+					{Name: "code", Type: ColTypeString, Size: 10, Nullable: true, UniqueKey: true},
+				},
+			},
+			{
+				Name: "user",
+				Columns: []*Column{
+					{Name: "id", Type: ColTypeLong, PrimaryKey: true},
+					{Name: "group_id", Type: ColTypeLong},
+					{Name: "name", Type: ColTypeString, Size: 40, UniqueKey: true},
+				},
+			},
+		},
+	}
+	if diff := cmp.Diff(expected, schema); diff != "" {
+		t.Errorf("TestMysql_FromString_DropConstraint() mismatch (-expected +actual):\n%s", diff)
+	}
+}
+
 func TestMysql_ToString(t *testing.T) {
 	schema := Schema{
 		Author:  "Author",
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"github.com/iancoleman/strcase"
 	"io/ioutil"
 	"log"
 	"sort"
@@ -15,6 +16,25 @@ type Reference struct {
 	Column string `json:"column,omitempty"`
 }
 
+// Target is generator specific extension of table or column.
+// targets are keyed by generator name. e.g. jpa-kotlin, gorm, sqlalchemy, graphql
+type Target struct {
+	// class name of table, or field name of column
+	Name string `json:"name,omitempty"`
+	// field type of column
+	Type        string   `json:"type,omitempty"`
+	Annotations []string `json:"annotations,omitempty"`
+	Imports     []string `json:"imports,omitempty"`
+}
+
+// getTarget returns target of the generator. empty target is returned if not found.
+func getTarget(targets map[string]*Target, name string) *Target {
+	if target, ok := targets[name]; ok && target != nil {
+		return target
+	}
+	return &Target{}
+}
+
 type Column struct {
 	Name            string     `json:"name"`
 	Type            string     `json:"type"`
@@ -27,6 +47,17 @@ type Column struct {
 	AutoIncremental bool       `json:"autoinc,omitempty"`
 	DefaultValue    string     `json:"default,omitempty"`
 	Ref             *Reference `json:"ref,omitempty"`
+
+	Targets map[string]*Target `json:"targets,omitempty"`
+
+	// embed which includes the column. set if the column is flattened from embeddable.
+	Embed *Embed `json:"-"`
+	// column of embeddable
+	EmbeddableColumn *Column `json:"-"`
+}
+
+func (c *Column) Target(name string) *Target {
+	return getTarget(c.Targets, name)
 }
 
 func (c *Column) IsRenamed(target *Column, excludeDescription bool) bool {
@@ -58,12 +89,50 @@ func (c *Column) Validate(autoCorrect bool) error {
 	return nil
 }
 
+// Embeddable is a reusable group of columns. e.g. address, money
+type Embeddable struct {
+	Name    string    `json:"name"`
+	Columns []*Column `json:"columns"`
+}
+
+// Embed includes columns of embeddable to the table. column names are prefixed.
+type Embed struct {
+	Embeddable string `json:"embeddable"`
+	Prefix     string `json:"prefix,omitempty"`
+	// field name of embedded class. derived from prefix if empty.
+	Name string `json:"name,omitempty"`
+}
+
+// FieldName returns field name of embedded class in lower camel case.
+func (e *Embed) FieldName() string {
+	if e.Name != "" {
+		return e.Name
+	}
+	if prefix := strings.Trim(e.Prefix, "_"); prefix != "" {
+		return strcase.ToLowerCamel(prefix)
+	}
+	return strcase.ToLowerCamel(e.Embeddable)
+}
+
+type Index struct {
+	Name    string   `json:"name"`
+	Columns []string `json:"columns"`
+}
+
 type Table struct {
 	Name        string    `json:"name,omitempty"`
 	Columns     []*Column `json:"columns,omitempty"`
 	Description string    `json:"desc,omitempty"`
 	Group       string    `json:"group,omitempty"`
 	ClassName   string    `json:"className,omitempty"`
+	Indices     []*Index  `json:"indices,omitempty"`
+	Embeds      []*Embed  `json:"embeds,omitempty"`
+
+	Targets map[string]*Target `json:"targets,omitempty"`
+}
+
+func (t *Table) Target(name string) *Target {
+	return getTarget(t.Targets, name)
 }
 
 func (t *Table) AddColumn(column *Column) {
@@ -118,10 +187,21 @@ func (s TableSlice) Less(i, j int) bool {
 func (s TableSlice) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
 
 type Schema struct {
-	Author  string   `json:"author,omitempty"`
-	Name    string   `json:"name,omitempty"`
-	Version string   `json:"version,omitempty"`
-	Tables  []*Table `json:"tables,omitempty"`
+	Author      string        `json:"author,omitempty"`
+	Name        string        `json:"name,omitempty"`
+	Version     string        `json:"version,omitempty"`
+	Embeddables []*Embeddable `json:"embeddables,omitempty"`
+	Tables      []*Table      `json:"tables,omitempty"`
+}
+
+// EmbeddableByName returns embeddable of the given name. nil is returned if not found.
+func (s *Schema) EmbeddableByName(name string) *Embeddable {
+	for _, embeddable := range s.Embeddables {
+		if embeddable.Name == name {
+			return embeddable
+		}
+	}
+	return nil
 }
 
 func (s *Schema) TableByName() map[string]*Table {
@@ -158,7 +238,21 @@ func (s *Schema) ToFile(filename string) error {
 
 func (s *Schema) ToJson() ([]byte, error) {
 	s.Normalize()
-	return json.MarshalIndent(s, "", "  ")
+
+	// flattened columns are not written since they're included by embeds
+	schema := *s
+	schema.Tables = make([]*Table, 0, len(s.Tables))
+	for _, table := range s.Tables {
+		t := *table
+		t.Columns = make([]*Column, 0, len(table.Columns))
+		for _, column := range table.Columns {
+			if column.Embed == nil {
+				t.Columns = append(t.Columns, column)
+			}
+		}
+		schema.Tables = append(schema.Tables, &t)
+	}
+	return json.MarshalIndent(&schema, "", "  ")
 }
 
 func (s *Schema) FromFile(filename string) error {
@@ -177,6 +271,15 @@ func (s *Schema) FromJson(data []byte) error {
 func (s *Schema) Normalize() {
 	sort.Sort(TableSlice(s.Tables))
 
+	for _, embeddable := range s.Embeddables {
+		for _, column := range embeddable.Columns {
+			if colType, ok := normalizeColumnType(column); ok {
+				column.Type = colType
+			}
+		}
+	}
+	s.flattenEmbeds()
+
 	for _, table := range s.Tables {
 		for _, column := range table.Columns {
 			if colType, ok := normalizeColumnType(column); ok {
@@ -194,6 +297,37 @@ func (s *Schema) Normalize() {
 	}
 }
 
+// flattenEmbeds adds columns of embeddables to the tables with prefixed names.
+// columns flattened by previous calls are kept, so it can be called multiple times.
+// columns defined in the table are not replaced by embedded columns of the same name.
+func (s *Schema) flattenEmbeds() {
+	for _, table := range s.Tables {
+		for _, embed := range table.Embeds {
+			embeddable := s.EmbeddableByName(embed.Embeddable)
+			if embeddable == nil {
+				log.Printf("embeddable not found. table: %s, embeddable: %s", table.Name, embed.Embeddable)
+				continue
+			}
+
+			columnByName := table.ColumnByName()
+			for _, embeddableColumn := range embeddable.Columns {
+				name := embed.Prefix + embeddableColumn.Name
+				if column, ok := columnByName[name]; ok {
+					if column.Embed != embed {
+						log.Printf("embedded column is ignored. column already exists. table: %s, column: %s", table.Name, name)
+					}
+					continue
+				}
+				column := *embeddableColumn
+				column.Name = name
+				column.Embed = embed
+				column.EmbeddableColumn = embeddableColumn
+				table.AddColumn(&column)
+			}
+		}
+	}
+}
+
 // normalizeColumnType converts column type to octopus generalized column type
 func normalizeColumnType(col *Column) (string, bool) {
 	colType := strings.ToLower(col.Type)
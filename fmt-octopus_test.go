@@ -0,0 +1,82 @@
+package main
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"testing"
+)
+
+func newEmbedTestSchema() *Schema {
+	return &Schema{
+		Embeddables: []*Embeddable{
+			{
+				Name: "address",
+				Columns: []*Column{
+					{Name: "city", Type: "varchar", Size: 40},
+					{Name: "zip_code", Type: "varchar", Size: 10, Nullable: true},
+				},
+			},
+		},
+		Tables: []*Table{
+			{
+				Name: "user",
+				Columns: []*Column{
+					{Name: "id", Type: "bigint", PrimaryKey: true},
+				},
+				Embeds: []*Embed{
+					{Embeddable: "address", Prefix: "home_"},
+					{Embeddable: "address", Prefix: "work_"},
+				},
+			},
+		},
+	}
+}
+
+func TestSchema_flattenEmbeds(t *testing.T) {
+	schema := newEmbedTestSchema()
+	schema.Tables[0].AddColumn(&Column{Name: "home_city", Type: "varchar", Size: 20})
+	schema.Normalize()
+	// flattened columns are not added again
+	schema.Normalize()
+
+	table := schema.Tables[0]
+	homeEmbed, workEmbed := table.Embeds[0], table.Embeds[1]
+	city, zipCode := schema.Embeddables[0].Columns[0], schema.Embeddables[0].Columns[1]
+
+	// column defined in the table is not replaced by embedded column
+	expected := []*Column{
+		{Name: "id", Type: ColTypeLong, PrimaryKey: true},
+		{Name: "home_city", Type: ColTypeString, Size: 20},
+		{Name: "home_zip_code", Type: ColTypeString, Size: 10, Nullable: true, Embed: homeEmbed, EmbeddableColumn: zipCode},
+		{Name: "work_city", Type: ColTypeString, Size: 40, Embed: workEmbed, EmbeddableColumn: city},
+		{Name: "work_zip_code", Type: ColTypeString, Size: 10, Nullable: true, Embed: workEmbed, EmbeddableColumn: zipCode},
+	}
+	if diff := cmp.Diff(expected, table.Columns); diff != "" {
+		t.Errorf("TestSchema_flattenEmbeds() mismatch (-expected +actual):\n%s", diff)
+	}
+}
+
+func TestSchema_ToJson_Embeds(t *testing.T) {
+	schema := newEmbedTestSchema()
+	schema.Tables[0].AddColumn(&Column{Name: "home_city", Type: "varchar", Size: 20})
+	data, err := schema.ToJson()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actual := &Schema{}
+	if err := actual.FromJson(data); err != nil {
+		t.Fatal(err)
+	}
+
+	// only flattened columns are dropped
+	expected := []*Column{
+		{Name: "id", Type: ColTypeLong, PrimaryKey: true},
+		{Name: "home_city", Type: ColTypeString, Size: 20},
+	}
+	if diff := cmp.Diff(expected, actual.Tables[0].Columns); diff != "" {
+		t.Errorf("TestSchema_ToJson_Embeds() mismatch (-expected +actual):\n%s", diff)
+	}
+	if diff := cmp.Diff(schema.Tables[0].Embeds, actual.Tables[0].Embeds); diff != "" {
+		t.Errorf("TestSchema_ToJson_Embeds() embeds mismatch (-expected +actual):\n%s", diff)
+	}
+}
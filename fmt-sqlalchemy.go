@@ -16,6 +16,10 @@ type SaClass struct {
 	Fields       []*SaField
 	PKFields     []*SaField
 	UniqueFields []*SaField
+	// decorators
+	Annotations []string
+	// import statements
+	ImportLines []string
 }
 
 type SaField struct {
@@ -24,6 +28,10 @@ type SaField struct {
 	OverrideName bool
 	Type         string
 	Imports      []string
+	// import statements
+	ImportLines []string
+	// additional Column() arguments
+	Arguments []string
 }
 
 type SqlAlchemy struct {
@@ -47,6 +55,11 @@ func NewSaClass(
 		}
 	}
 
+	target := table.Target(FormatSqlalchemy)
+	if target.Name != "" {
+		className = target.Name
+	}
+
 	fields := make([]*SaField, 0)
 	pkFields := make([]*SaField, 0)
 	uniqueFields := make([]*SaField, 0)
@@ -65,6 +78,8 @@ func NewSaClass(
 	return &SaClass{
 		table:        table,
 		Name:         className,
+		Annotations:  target.Annotations,
+		ImportLines:  target.Imports,
 		Fields:       fields,
 		PKFields:     pkFields,
 		UniqueFields: uniqueFields,
@@ -111,7 +126,11 @@ func NewSaField(column *Column) *SaField {
 		fieldType = ""
 	}
 
-	if fieldType != "" {
+	// override by target
+	target := column.Target(FormatSqlalchemy)
+	if target.Type != "" {
+		fieldType = target.Type
+	} else if fieldType != "" {
 		importSet.Add(fieldType)
 	}
 
@@ -122,6 +141,10 @@ func NewSaField(column *Column) *SaField {
 	if reservedWord {
 		fieldName = fieldName + "_"
 	}
+	if target.Name != "" {
+		fieldName = target.Name
+		ok = false
+	}
 
 	return &SaField{
 		Column:       column,
@@ -129,6 +152,8 @@ func NewSaField(column *Column) *SaField {
 		OverrideName: reservedWord || !ok,
 		Type:         fieldType,
 		Imports:      importSet.Slice(),
+		ImportLines:  target.Imports,
+		Arguments:    target.Annotations,
 	}
 }
 
@@ -148,6 +173,10 @@ func (sa *SqlAlchemy) Generate(
 ) error {
 	uniqueNameSuffix := output.Get(FlagUniqueNameSuffix)
 	useUTC := output.GetBool(FlagUseUTC)
+	audit, err := newAuditColumns(output.Get(FlagAudit))
+	if err != nil {
+		return err
+	}
 
 	// write to single file if extension is '.py'
 	var outputDir string
@@ -200,7 +229,10 @@ func (sa *SqlAlchemy) Generate(
 		}
 
 		// class
-		appendLine("", "",
+		appendLine("", "")
+		appendLine(class.Annotations...)
+		importSet.AddAll(class.ImportLines)
+		appendLine(
 			fmt.Sprintf("class %s(Base):", class.Name),
 			indent+fmt.Sprintf("__tablename__ = '%s'", table.Name),
 		)
@@ -223,7 +255,15 @@ func (sa *SqlAlchemy) Generate(
 				attributes = append(attributes, Quote(column.Name, "'"))
 			}
 
-			if lcColumnType == ColTypeString && column.Size > 0 {
+			auditRole := ""
+			if lcColumnType == ColTypeDateTime {
+				auditRole = audit.GetRole(column)
+			}
+			isAuditTime := auditRole == AuditCreated || auditRole == AuditUpdated
+
+			if column.Target(FormatSqlalchemy).Type != "" {
+				attributes = append(attributes, field.Type)
+			} else if lcColumnType == ColTypeString && column.Size > 0 {
 				attributes = append(attributes, fmt.Sprintf("%s(%d)", field.Type, column.Size))
 			} else if lcColumnType == ColTypeDouble || lcColumnType == ColTypeFloat || lcColumnType == ColTypeDecimal {
 				colAttrs := make([]string, 0)
@@ -235,33 +275,29 @@ func (sa *SqlAlchemy) Generate(
 				}
 
 				attributes = append(attributes, fmt.Sprintf("%s(%s)", field.Type, strings.Join(colAttrs, ", ")))
-			} else if lcColumnType == ColTypeDateTime {
-				if column.Name == "created_at" {
-					if useUTC {
-						attributes = append(attributes, field.Type, "default=datetime.utcnow")
-					} else {
-						attributes = append(attributes, field.Type, "default=datetime.now")
-					}
-				} else if column.Name == "updated_at" {
-					if useUTC {
-						attributes = append(attributes, field.Type, "default=datetime.utcnow", "onupdate=datetime.utcnow")
-					} else {
-						attributes = append(attributes, field.Type, "default=datetime.now", "onupdate=datetime.now")
-					}
-				} else {
-					if useUTC {
-						attributes = append(attributes, "TZDateTime")
-						useTZDateTime = true
-						saImportSet.Add("TypeDecorator")
-						importSet.Add("from datetime import timezone")
-					} else {
-						attributes = append(attributes, field.Type)
-					}
-				}
-				importSet.Add("from datetime import datetime")
+			} else if lcColumnType == ColTypeDateTime && useUTC && !isAuditTime {
+				attributes = append(attributes, "TZDateTime")
+				useTZDateTime = true
+				saImportSet.Add("TypeDecorator")
+				importSet.Add("from datetime import timezone")
 			} else {
 				attributes = append(attributes, field.Type)
 			}
+
+			// audit timestamps are set regardless of target type
+			if lcColumnType == ColTypeDateTime {
+				now := "datetime.now"
+				if useUTC {
+					now = "datetime.utcnow"
+				}
+				if isAuditTime {
+					attributes = append(attributes, "default="+now)
+				}
+				if auditRole == AuditUpdated {
+					attributes = append(attributes, "onupdate="+now)
+				}
+				importSet.Add("from datetime import datetime")
+			}
 			// PK
 			if column.PrimaryKey {
 				attributes = append(attributes, "primary_key=True")
@@ -279,12 +315,23 @@ func (sa *SqlAlchemy) Generate(
 				attributes = append(attributes, "nullable=False")
 			}
 
+			attributes = append(attributes, field.Arguments...)
+
 			appendLine(indent + fmt.Sprintf("%s = Column(%s)", field.Name, strings.Join(attributes, ", ")))
 
 			// import
 			for _, imp := range field.Imports {
 				saImportSet.Add(imp)
 			}
+			importSet.AddAll(field.ImportLines)
+		}
+
+		// optimistic locking
+		for _, field := range class.Fields {
+			if audit.GetRole(field.Column) == AuditVersion {
+				appendLine("", indent+fmt.Sprintf("__mapper_args__ = {'version_id_col': %s}", field.Name))
+				break
+			}
 		}
 
 		if generateSingleFile {
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestSqlAlchemy_Generate_Target(t *testing.T) {
+	schema := &Schema{
+		Tables: []*Table{
+			{
+				Name: "user",
+				Columns: []*Column{
+					{Name: "id", Type: ColTypeLong, PrimaryKey: true, AutoIncremental: true},
+					{
+						Name: "class",
+						Type: ColTypeString,
+						Size: 20,
+						Targets: map[string]*Target{
+							FormatSqlalchemy: {
+								Name:        "user_class",
+								Type:        "Enum(UserClass)",
+								Annotations: []string{"default=UserClass.NORMAL"},
+								Imports:     []string{"from .enums import UserClass"},
+							},
+						},
+					},
+					{
+						Name: "created_at",
+						Type: ColTypeDateTime,
+						Targets: map[string]*Target{
+							FormatSqlalchemy: {Type: "DateTime(timezone=True)"},
+						},
+					},
+					{
+						Name: "updated_at",
+						Type: ColTypeDateTime,
+						Targets: map[string]*Target{
+							FormatSqlalchemy: {Type: "DateTime(timezone=True)"},
+						},
+					},
+					{Name: "deleted_at", Type: ColTypeDateTime, Nullable: true},
+				},
+				Targets: map[string]*Target{
+					FormatSqlalchemy: {
+						Name:        "Member",
+						Annotations: []string{"@dataclass"},
+						Imports:     []string{"from dataclasses import dataclass"},
+					},
+				},
+			},
+		},
+	}
+
+	files, err := generateOutput(t, schema, &Output{FilePath: "models.py", Format: FormatSqlalchemy, Options: map[string]string{
+		FlagAudit:  "created:created_at,updated:updated_at",
+		FlagUseUTC: "true",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual := files["models.py"]
+
+	assertContains(t, "TestSqlAlchemy_Generate_Target()", actual,
+		"from .enums import UserClass",
+		"from dataclasses import dataclass",
+		"from datetime import datetime",
+		"from datetime import timezone",
+		"",
+		"from sqlalchemy import BigInteger, Column, DateTime, TypeDecorator",
+	)
+	// audit defaults are set even if type is overridden by target
+	assertContains(t, "TestSqlAlchemy_Generate_Target()", actual,
+		"@dataclass",
+		"class Member(Base):",
+		"    __tablename__ = 'user'",
+		"",
+		"    id = Column(BigInteger, primary_key=True, autoincrement=True)",
+		"    user_class = Column('class', Enum(UserClass), nullable=False, default=UserClass.NORMAL)",
+		"    created_at = Column(DateTime(timezone=True), default=datetime.utcnow, nullable=False)",
+		"    updated_at = Column(DateTime(timezone=True), default=datetime.utcnow, onupdate=datetime.utcnow, nullable=False)",
+		"    deleted_at = Column(TZDateTime)",
+	)
+}
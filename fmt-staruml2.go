@@ -1,15 +1,22 @@
 package main
 
 import (
+	"encoding/base64"
+	"encoding/binary"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io/ioutil"
+	"log"
+	"math"
+	"strconv"
 	"strings"
 )
 
 type StarUML2 struct {
 	root    *StarUML2Element
 	mapById map[string]*StarUML2Element
+	idSeq   uint64
 }
 
 type StarUML2Ref struct {
@@ -19,22 +26,45 @@ type StarUML2Ref struct {
 type StarUML2Element struct {
 	ElemType      string       `json:"_type"`
 	ID            string       `json:"_id"`
-	Parent        *StarUML2Ref `json:"_parent"`
-	Name          string       `json:"name"`
-	Length        interface{}  `json:"length"`
-	Type          string       `json:"type"`
-	PrimaryKey    bool         `json:"primaryKey"`
-	Unique        bool         `json:"unique"`
-	Nullable      bool         `json:"nullable"`
-	Documentation string       `json:"documentation"`
-	Head          *StarUML2Ref `json:"head"`
-	End1          *StarUML2Ref `json:"end1"`
-	End2          *StarUML2Ref `json:"end2"`
-	Reference     *StarUML2Ref `json:"reference"`
-	ReferenceTo   *StarUML2Ref `json:"referenceTo"`
-
-	OwnedElements []*StarUML2Element `json:"ownedElements"`
-	Columns       []*StarUML2Element `json:"columns"`
+	Parent        *StarUML2Ref `json:"_parent,omitempty"`
+	Name          string       `json:"name,omitempty"`
+	Length        interface{}  `json:"length,omitempty"`
+	Type          string       `json:"type,omitempty"`
+	PrimaryKey    bool         `json:"primaryKey,omitempty"`
+	Unique        bool         `json:"unique,omitempty"`
+	Nullable      bool         `json:"nullable,omitempty"`
+	Documentation string       `json:"documentation,omitempty"`
+	Head          *StarUML2Ref `json:"head,omitempty"`
+	Reference     *StarUML2Ref `json:"reference,omitempty"`
+	ReferenceTo   *StarUML2Ref `json:"referenceTo,omitempty"`
+
+	// relationship attributes
+	End1        *StarUML2Element `json:"end1,omitempty"`
+	End2        *StarUML2Element `json:"end2,omitempty"`
+	Cardinality string           `json:"cardinality,omitempty"`
+
+	// tag attributes
+	Kind    string `json:"kind,omitempty"`
+	Value   string `json:"value,omitempty"`
+	Checked bool   `json:"checked,omitempty"`
+
+	// view attributes
+	Model             *StarUML2Ref `json:"model,omitempty"`
+	DefaultDiagram    bool         `json:"defaultDiagram,omitempty"`
+	Font              string       `json:"font,omitempty"`
+	Text              string       `json:"text,omitempty"`
+	Left              int          `json:"left,omitempty"`
+	Top               int          `json:"top,omitempty"`
+	Width             int          `json:"width,omitempty"`
+	Height            int          `json:"height,omitempty"`
+	NameLabel         *StarUML2Ref `json:"nameLabel,omitempty"`
+	ColumnCompartment *StarUML2Ref `json:"columnCompartment,omitempty"`
+
+	OwnedElements []*StarUML2Element `json:"ownedElements,omitempty"`
+	Columns       []*StarUML2Element `json:"columns,omitempty"`
+	OwnedViews    []*StarUML2Element `json:"ownedViews,omitempty"`
+	SubViews      []*StarUML2Element `json:"subViews,omitempty"`
+	Tags          []*StarUML2Element `json:"tags,omitempty"`
 }
 
 func (f *StarUML2) FromFile(filename string) error {
@@ -74,48 +104,35 @@ func (f *StarUML2) ToSchema() (*Schema, error) {
 		return nil, errors.New("ERDDataModel not found")
 	}
 
-	erdEntities := f.findByType(erdDataModels[0].OwnedElements, "ERDEntity")
-	tables := make([]*Table, 0)
-	for _, erdEntity := range erdEntities {
-		// Create Columns
-		columns := make([]*Column, 0)
-		for _, erdColumn := range f.findByType(erdEntity.Columns, "ERDColumn") {
-			colType, colSize, colScale := ParseType(erdColumn.Type)
-			size := uint16(ToInt(erdColumn.Length, 0))
-			if size == 0 {
-				size = colSize
-			}
+	// data model name is used as table group if there are multiple data models
+	useGroup := len(erdDataModels) > 1
 
-			column := &Column{
-				Name:            erdColumn.Name,
-				Type:            colType,
-				Size:            size,
-				Scale:           colScale,
-				Nullable:        erdColumn.Nullable,
-				PrimaryKey:      erdColumn.PrimaryKey,
-				UniqueKey:       erdColumn.Unique,
-				AutoIncremental: false,
-				Description:     strings.TrimSpace(erdColumn.Documentation),
-			}
+	tables := make([]*Table, 0)
+	tableByEntityId := make(map[string]*Table)
+	relationships := make([]*StarUML2Element, 0)
+	for _, erdDataModel := range erdDataModels {
+		group := ""
+		if useGroup {
+			group = erdDataModel.Name
+		}
 
-			if erdColumn.ReferenceTo != nil {
-				targetColumn := f.findById(erdColumn.ReferenceTo.Ref)
-				targetTable := f.findById(targetColumn.Parent.Ref)
-				column.Ref = &Reference{
-					Table:  targetTable.Name,
-					Column: targetColumn.Name,
-				}
+		for _, erdEntity := range f.findByType(erdDataModel.OwnedElements, "ERDEntity") {
+			table, err := f.toTable(erdEntity, group)
+			if err != nil {
+				return nil, err
 			}
+			tables = append(tables, table)
+			tableByEntityId[erdEntity.ID] = table
 
-			columns = append(columns, column)
+			relationships = append(relationships, f.findByType(erdEntity.OwnedElements, "ERDRelationship")...)
 		}
+		relationships = append(relationships, f.findByType(erdDataModel.OwnedElements, "ERDRelationship")...)
+	}
 
-		// Create Table
-		table := &Table{
-			Name:    erdEntity.Name,
-			Columns: columns,
+	for _, relationship := range relationships {
+		if err := f.applyRelationship(relationship, tableByEntityId); err != nil {
+			return nil, err
 		}
-		tables = append(tables, table)
 	}
 
 	schema := Schema{
@@ -125,6 +142,156 @@ func (f *StarUML2) ToSchema() (*Schema, error) {
 	return &schema, nil
 }
 
+func (f *StarUML2) toTable(erdEntity *StarUML2Element, group string) (*Table, error) {
+	// Create Columns
+	columns := make([]*Column, 0)
+	for _, erdColumn := range f.findByType(erdEntity.Columns, "ERDColumn") {
+		colType, colSize, colScale := ParseType(erdColumn.Type)
+		size := uint16(ToInt(erdColumn.Length, 0))
+		if size == 0 {
+			size = colSize
+		}
+
+		column := &Column{
+			Name:            erdColumn.Name,
+			Type:            colType,
+			Size:            size,
+			Scale:           colScale,
+			Nullable:        erdColumn.Nullable,
+			PrimaryKey:      erdColumn.PrimaryKey,
+			UniqueKey:       erdColumn.Unique,
+			AutoIncremental: f.isAutoIncremental(erdColumn),
+			Description:     strings.TrimSpace(erdColumn.Documentation),
+		}
+
+		if erdColumn.ReferenceTo != nil {
+			targetColumn := f.findById(erdColumn.ReferenceTo.Ref)
+			if targetColumn == nil {
+				return nil, fmt.Errorf("entity: %s, column: %s, referenced column not found: %s",
+					erdEntity.Name, erdColumn.Name, erdColumn.ReferenceTo.Ref)
+			}
+			var targetTable *StarUML2Element
+			if targetColumn.Parent != nil {
+				targetTable = f.findById(targetColumn.Parent.Ref)
+			}
+			if targetTable == nil {
+				return nil, fmt.Errorf("entity: %s, column: %s, entity of referenced column not found: %s",
+					erdEntity.Name, erdColumn.Name, targetColumn.Name)
+			}
+			column.Ref = &Reference{
+				Table:  targetTable.Name,
+				Column: targetColumn.Name,
+			}
+		}
+
+		columns = append(columns, column)
+	}
+
+	// Create Table
+	return &Table{
+		Name:        erdEntity.Name,
+		Columns:     columns,
+		Description: strings.TrimSpace(erdEntity.Documentation),
+		Group:       group,
+	}, nil
+}
+
+// isAutoIncremental returns true if column has auto increment tag.
+func (f *StarUML2) isAutoIncremental(erdColumn *StarUML2Element) bool {
+	for _, tag := range erdColumn.Tags {
+		switch strings.ToLower(tag.Name) {
+		case "autoincrement", "auto_increment", "autoinc", "identity":
+			if tag.Kind == "boolean" {
+				return tag.Checked
+			}
+			return strings.ToLower(tag.Value) != "false"
+		}
+	}
+	return false
+}
+
+// applyRelationship sets column reference of the entity on the 'many' side of the relationship.
+// Optional cardinality on the 'one' side makes foreign key column nullable,
+// and single cardinality on the other side makes it unique.
+func (f *StarUML2) applyRelationship(relationship *StarUML2Element, tableByEntityId map[string]*Table) error {
+	end1, end2 := relationship.End1, relationship.End2
+	if end1 == nil || end2 == nil || end1.Reference == nil || end2.Reference == nil {
+		return fmt.Errorf("relationship: %s, invalid relationship ends", relationship.ID)
+	}
+
+	parentEnd, childEnd := end1, end2
+	if f.isManyCardinality(end1.Cardinality) {
+		if f.isManyCardinality(end2.Cardinality) {
+			log.Printf("many-to-many relationship is not supported. relationship: %s", relationship.ID)
+			return nil
+		}
+		parentEnd, childEnd = end2, end1
+	}
+
+	parentTable := tableByEntityId[parentEnd.Reference.Ref]
+	childTable := tableByEntityId[childEnd.Reference.Ref]
+	if parentTable == nil || childTable == nil {
+		return fmt.Errorf("relationship: %s, related entity not found", relationship.ID)
+	}
+
+	fkColumn := f.findForeignKeyColumn(childTable, parentTable)
+	if fkColumn == nil {
+		log.Printf("foreign key column not found. relationship: %s -> %s", childTable.Name, parentTable.Name)
+		return nil
+	}
+
+	if parentEnd.Cardinality == "0..1" {
+		fkColumn.Nullable = true
+	}
+	if !f.isManyCardinality(childEnd.Cardinality) && !fkColumn.PrimaryKey {
+		fkColumn.UniqueKey = true
+	}
+	return nil
+}
+
+// findForeignKeyColumn finds a column of childTable referencing parentTable.
+// Column named '<parent>_<pk>' or same name of single primary key is used if reference is not set.
+// nil is returned if no column matches.
+func (f *StarUML2) findForeignKeyColumn(childTable *Table, parentTable *Table) *Column {
+	for _, column := range childTable.Columns {
+		if column.Ref != nil && column.Ref.Table == parentTable.Name {
+			return column
+		}
+	}
+
+	pkNames := parentTable.PrimaryKeyNameSet().Slice()
+	if len(pkNames) != 1 {
+		return nil
+	}
+	pkName := pkNames[0]
+	columnByName := childTable.ColumnByName()
+	childPkCount := childTable.PrimaryKeyNameSet().Size()
+
+	candidates := []string{parentTable.Name + "_" + pkName, pkName}
+	for _, name := range candidates {
+		column, ok := columnByName[name]
+		if !ok || column.Ref != nil {
+			continue
+		}
+		// single primary key column of child table cannot be a foreign key with the same name
+		if column.PrimaryKey && childPkCount == 1 && name == pkName {
+			continue
+		}
+		log.Printf("foreign key column is guessed by name. relationship: %s.%s -> %s.%s",
+			childTable.Name, name, parentTable.Name, pkName)
+		column.Ref = &Reference{
+			Table:  parentTable.Name,
+			Column: pkName,
+		}
+		return column
+	}
+	return nil
+}
+
+func (f *StarUML2) isManyCardinality(cardinality string) bool {
+	return strings.Contains(cardinality, "*")
+}
+
 func (f *StarUML2) findByType(elems []*StarUML2Element, typ string) []*StarUML2Element {
 	result := make([]*StarUML2Element, 0)
 
@@ -158,6 +325,275 @@ func (f *StarUML2) findById(id string) *StarUML2Element {
 }
 
 func (f *StarUML2) ToFile(schema *Schema, filename string) error {
-	// TODO
-	return errors.New("not implemented")
+	data, err := f.ToJson(schema)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(filename, data, 0644)
+}
+
+func (f *StarUML2) ToJson(schema *Schema) ([]byte, error) {
+	f.idSeq = 0
+
+	project := &StarUML2Element{
+		ElemType: "Project",
+		ID:       f.newId(),
+		Name:     schema.Version,
+	}
+
+	// create data model for each group
+	project.OwnedElements = make([]*StarUML2Element, 0)
+	entitiesByModel := make(map[*StarUML2Element][]*StarUML2Element)
+	entityByTable := make(map[*Table]*StarUML2Element)
+	erdColumnByName := make(map[string]*StarUML2Element)
+	for _, group := range schema.Groups() {
+		dataModel := &StarUML2Element{
+			ElemType: "ERDDataModel",
+			ID:       f.newId(),
+			Parent:   f.refOf(project),
+			Name:     TernaryString(group == "", "Data Model", group),
+		}
+		project.OwnedElements = append(project.OwnedElements, dataModel)
+
+		entities := make([]*StarUML2Element, 0)
+		for _, table := range schema.Tables {
+			if table.Group != group {
+				continue
+			}
+			entity := &StarUML2Element{
+				ElemType:      "ERDEntity",
+				ID:            f.newId(),
+				Parent:        f.refOf(dataModel),
+				Name:          table.Name,
+				Documentation: table.Description,
+				Columns:       make([]*StarUML2Element, 0),
+			}
+			for _, column := range table.Columns {
+				erdColumn := f.newErdColumn(entity, column)
+				entity.Columns = append(entity.Columns, erdColumn)
+				erdColumnByName[table.Name+"."+column.Name] = erdColumn
+			}
+			entities = append(entities, entity)
+			entityByTable[table] = entity
+		}
+		entitiesByModel[dataModel] = entities
+	}
+
+	// set references
+	for _, table := range schema.Tables {
+		entity := entityByTable[table]
+		for j, column := range table.Columns {
+			if column.Ref == nil {
+				continue
+			}
+			if target, ok := erdColumnByName[column.Ref.Table+"."+column.Ref.Column]; ok {
+				entity.Columns[j].ReferenceTo = f.refOf(target)
+			} else {
+				log.Printf("reference not found. table: %s, column: %s -> %s.%s",
+					table.Name, column.Name, column.Ref.Table, column.Ref.Column)
+			}
+		}
+	}
+
+	for _, dataModel := range project.OwnedElements {
+		entities := entitiesByModel[dataModel]
+
+		// create entity views
+		diagram := &StarUML2Element{
+			ElemType:       "ERDDiagram",
+			ID:             f.newId(),
+			Parent:         f.refOf(dataModel),
+			Name:           "ERDDiagram",
+			DefaultDiagram: dataModel == project.OwnedElements[0],
+		}
+		diagram.OwnedViews = f.layoutEntityViews(diagram, entities)
+
+		dataModel.OwnedElements = append([]*StarUML2Element{diagram}, entities...)
+	}
+
+	return json.MarshalIndent(project, "", "\t")
+}
+
+func (f *StarUML2) newErdColumn(entity *StarUML2Element, column *Column) *StarUML2Element {
+	erdColumn := &StarUML2Element{
+		ElemType:      "ERDColumn",
+		ID:            f.newId(),
+		Parent:        f.refOf(entity),
+		Name:          column.Name,
+		PrimaryKey:    column.PrimaryKey,
+		Unique:        column.UniqueKey,
+		Nullable:      column.Nullable,
+		Documentation: column.Description,
+	}
+
+	if column.AutoIncremental {
+		erdColumn.Tags = []*StarUML2Element{
+			{
+				ElemType: "Tag",
+				ID:       f.newId(),
+				Parent:   f.refOf(erdColumn),
+				Name:     "autoIncrement",
+				Kind:     "boolean",
+				Checked:  true,
+			},
+		}
+	}
+
+	colType := f.toStarUMLColumnType(column)
+	if column.Scale > 0 {
+		erdColumn.Type = fmt.Sprintf("%s(%d,%d)", colType, column.Size, column.Scale)
+	} else {
+		erdColumn.Type = colType
+		if column.Size > 0 {
+			erdColumn.Length = strconv.Itoa(int(column.Size))
+		}
+	}
+	return erdColumn
+}
+
+// layoutEntityViews places entity views on a grid.
+func (f *StarUML2) layoutEntityViews(diagram *StarUML2Element, entities []*StarUML2Element) []*StarUML2Element {
+	const (
+		font         = "Arial;13;0"
+		boldFont     = "Arial;13;1"
+		margin       = 40
+		gap          = 60
+		charWidth    = 8
+		rowHeight    = 16
+		headerHeight = 25
+		minWidth     = 120
+	)
+
+	views := make([]*StarUML2Element, 0)
+
+	columnCount := int(math.Ceil(math.Sqrt(float64(len(entities)))))
+	left, top := margin, margin
+	rowMaxHeight := 0
+
+	for i, entity := range entities {
+		if columnCount > 0 && i > 0 && i%columnCount == 0 {
+			left = margin
+			top += rowMaxHeight + gap
+			rowMaxHeight = 0
+		}
+
+		// calculate size
+		maxTextLen := len(entity.Name)
+		for _, erdColumn := range entity.Columns {
+			if textLen := len(erdColumn.Name) + len(erdColumn.Type) + 8; textLen > maxTextLen {
+				maxTextLen = textLen
+			}
+		}
+		width := maxTextLen*charWidth + 20
+		if width < minWidth {
+			width = minWidth
+		}
+		height := headerHeight + len(entity.Columns)*rowHeight + 10
+
+		view := &StarUML2Element{
+			ElemType: "ERDEntityView",
+			ID:       f.newId(),
+			Parent:   f.refOf(diagram),
+			Model:    f.refOf(entity),
+			Font:     font,
+			Left:     left,
+			Top:      top,
+			Width:    width,
+			Height:   height,
+		}
+
+		nameLabel := &StarUML2Element{
+			ElemType: "LabelView",
+			ID:       f.newId(),
+			Parent:   f.refOf(view),
+			Font:     boldFont,
+			Text:     entity.Name,
+			Left:     left + 5,
+			Top:      top + 5,
+			Width:    width - 10,
+			Height:   13,
+		}
+
+		compartment := &StarUML2Element{
+			ElemType: "ERDColumnCompartmentView",
+			ID:       f.newId(),
+			Parent:   f.refOf(view),
+			Model:    f.refOf(entity),
+			Font:     font,
+			Left:     left,
+			Top:      top + headerHeight,
+			Width:    width,
+			Height:   height - headerHeight,
+			SubViews: make([]*StarUML2Element, 0),
+		}
+		for j, erdColumn := range entity.Columns {
+			compartment.SubViews = append(compartment.SubViews, &StarUML2Element{
+				ElemType: "ERDColumnView",
+				ID:       f.newId(),
+				Parent:   f.refOf(compartment),
+				Model:    f.refOf(erdColumn),
+				Font:     font,
+				Left:     left + 5,
+				Top:      top + headerHeight + 5 + j*rowHeight,
+				Width:    width - 10,
+				Height:   13,
+			})
+		}
+
+		view.SubViews = []*StarUML2Element{nameLabel, compartment}
+		view.NameLabel = f.refOf(nameLabel)
+		view.ColumnCompartment = f.refOf(compartment)
+		views = append(views, view)
+
+		left += width + gap
+		if height > rowMaxHeight {
+			rowMaxHeight = height
+		}
+	}
+
+	return views
+}
+
+// newId generates element ID in StarUML format.
+func (f *StarUML2) newId() string {
+	f.idSeq++
+	buf := make([]byte, 14)
+	binary.BigEndian.PutUint64(buf[6:], f.idSeq)
+	return base64.StdEncoding.EncodeToString(buf)
+}
+
+func (f *StarUML2) refOf(elem *StarUML2Element) *StarUML2Ref {
+	return &StarUML2Ref{Ref: elem.ID}
+}
+
+func (f *StarUML2) toStarUMLColumnType(col *Column) string {
+	switch col.Type {
+	case ColTypeString:
+		return "VARCHAR"
+	case ColTypeText:
+		return "TEXT"
+	case ColTypeBoolean:
+		return "BOOLEAN"
+	case ColTypeLong:
+		return "BIGINT"
+	case ColTypeInt:
+		return "INT"
+	case ColTypeDecimal:
+		return "DECIMAL"
+	case ColTypeFloat:
+		return "FLOAT"
+	case ColTypeDouble:
+		return "DOUBLE"
+	case ColTypeDateTime:
+		return "DATETIME"
+	case ColTypeDate:
+		return "DATE"
+	case ColTypeTime:
+		return "TIME"
+	case ColTypeBlob:
+		return "BLOB"
+	default:
+		return strings.ToUpper(col.Type)
+	}
 }
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"strings"
+	"testing"
+)
+
+func TestStarUML2_ToJson(t *testing.T) {
+	schema := &Schema{
+		Version: "1.0.0",
+		Tables: []*Table{
+			{
+				Name:        "group",
+				Description: "user groups",
+				Group:       "common",
+				Columns: []*Column{
+					{Name: "id", Type: ColTypeLong, PrimaryKey: true, AutoIncremental: true},
+					{Name: "name", Type: ColTypeString, Size: 40, UniqueKey: true, Description: "group name"},
+				},
+			},
+			{
+				Name:  "user",
+				Group: "user",
+				Columns: []*Column{
+					{Name: "id", Type: ColTypeLong, PrimaryKey: true, AutoIncremental: true},
+					{Name: "group_id", Type: ColTypeLong, Nullable: true, Ref: &Reference{Table: "group", Column: "id"}},
+					{Name: "point", Type: ColTypeDecimal, Size: 10, Scale: 2},
+				},
+			},
+		},
+	}
+
+	writer := StarUML2{}
+	data, err := writer.ToJson(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// read written json
+	reader := StarUML2{}
+	if err := reader.FromJson(data); err != nil {
+		t.Fatal(err)
+	}
+	actual, err := reader.ToSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual.Normalize()
+
+	if diff := cmp.Diff(schema, actual); diff != "" {
+		t.Errorf("TestStarUML2_ToJson() mismatch (-expected +actual):\n%s", diff)
+	}
+}
+
+func TestStarUML2_ToJson_SingleGroup(t *testing.T) {
+	schema := &Schema{
+		Tables: []*Table{
+			{
+				Name: "user",
+				Columns: []*Column{
+					{Name: "id", Type: ColTypeLong, PrimaryKey: true},
+				},
+			},
+		},
+	}
+
+	writer := StarUML2{}
+	data, err := writer.ToJson(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reader := StarUML2{}
+	if err := reader.FromJson(data); err != nil {
+		t.Fatal(err)
+	}
+	dataModels := reader.findByType(reader.root.OwnedElements, "ERDDataModel")
+	if len(dataModels) != 1 || dataModels[0].Name != "Data Model" {
+		t.Errorf("TestStarUML2_ToJson_SingleGroup() unexpected data models: %v", dataModels)
+	}
+
+	actual, err := reader.ToSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual.Normalize()
+	if diff := cmp.Diff(schema, actual); diff != "" {
+		t.Errorf("TestStarUML2_ToJson_SingleGroup() mismatch (-expected +actual):\n%s", diff)
+	}
+}
+
+// newStarUML2TestEntity returns mdj of ERDEntity. columns are given as json.
+func newStarUML2TestEntity(name string, columns ...string) string {
+	return `{"_type": "ERDEntity", "_id": "` + name + `", "name": "` + name + `", "columns": [` +
+		strings.Join(columns, ", ") + `]}`
+}
+
+// newStarUML2TestRelationship returns mdj of ERDRelationship between entities.
+func newStarUML2TestRelationship(id, entity1, cardinality1, entity2, cardinality2 string) string {
+	return `{"_type": "ERDRelationship", "_id": "` + id + `",
+  "end1": {"_type": "ERDRelationshipEnd", "_id": "` + id + `.1", "reference": {"$ref": "` + entity1 + `"}, "cardinality": "` + cardinality1 + `"},
+  "end2": {"_type": "ERDRelationshipEnd", "_id": "` + id + `.2", "reference": {"$ref": "` + entity2 + `"}, "cardinality": "` + cardinality2 + `"}}`
+}
+
+func TestStarUML2_ToSchema(t *testing.T) {
+	mdj := `{"_type": "Project", "_id": "p", "name": "1.0.0", "ownedElements": [
+{"_type": "ERDDataModel", "_id": "dm1", "name": "common", "ownedElements": [` +
+		newStarUML2TestEntity("group",
+			`{"_type": "ERDColumn", "_id": "group.id", "_parent": {"$ref": "group"}, "name": "id", "type": "BIGINT", "primaryKey": true,
+  "tags": [{"_type": "Tag", "_id": "t1", "name": "AUTO_INCREMENT", "kind": "boolean", "checked": true}]}`,
+			`{"_type": "ERDColumn", "_id": "group.name", "_parent": {"$ref": "group"}, "name": "name", "type": "VARCHAR", "length": 40,
+  "documentation": " group name "}`,
+		) + `]},
+{"_type": "ERDDataModel", "_id": "dm2", "name": "user", "ownedElements": [` +
+		newStarUML2TestEntity("user",
+			`{"_type": "ERDColumn", "_id": "user.id", "_parent": {"$ref": "user"}, "name": "id", "type": "BIGINT", "primaryKey": true,
+  "tags": [{"_type": "Tag", "_id": "t2", "name": "identity", "kind": "string", "value": "true"}]}`,
+			`{"_type": "ERDColumn", "_id": "user.group_id", "_parent": {"$ref": "user"}, "name": "group_id", "type": "BIGINT"}`,
+			`{"_type": "ERDColumn", "_id": "user.profile_no", "_parent": {"$ref": "user"}, "name": "profile_no", "type": "BIGINT",
+  "referenceTo": {"$ref": "profile.no"}}`,
+		) + `, ` +
+		newStarUML2TestEntity("profile",
+			`{"_type": "ERDColumn", "_id": "profile.no", "_parent": {"$ref": "profile"}, "name": "no", "type": "BIGINT", "primaryKey": true,
+  "tags": [{"_type": "Tag", "_id": "t3", "name": "autoinc", "kind": "string", "value": "false"}]}`,
+		) + `, ` +
+		// optional one-to-many. foreign key column is guessed by name
+		newStarUML2TestRelationship("r1", "group", "0..1", "user", "0..*") + `, ` +
+		// one-to-one. foreign key column is referenced column
+		newStarUML2TestRelationship("r2", "profile", "1", "user", "1") + `, ` +
+		// foreign key column not found
+		newStarUML2TestRelationship("r3", "group", "0..*", "profile", "1") + `
+]}]}`
+
+	reader := StarUML2{}
+	if err := reader.FromJson([]byte(mdj)); err != nil {
+		t.Fatal(err)
+	}
+	actual, err := reader.ToSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual.Normalize()
+
+	expected := &Schema{
+		Version: "1.0.0",
+		Tables: []*Table{
+			{
+				Name:  "group",
+				Group: "common",
+				Columns: []*Column{
+					{Name: "id", Type: ColTypeLong, PrimaryKey: true, AutoIncremental: true},
+					{Name: "name", Type: ColTypeString, Size: 40, Description: "group name"},
+				},
+			},
+			{
+				Name:  "profile",
+				Group: "user",
+				Columns: []*Column{
+					{Name: "no", Type: ColTypeLong, PrimaryKey: true},
+				},
+			},
+			{
+				Name:  "user",
+				Group: "user",
+				Columns: []*Column{
+					{Name: "id", Type: ColTypeLong, PrimaryKey: true, AutoIncremental: true},
+					{Name: "group_id", Type: ColTypeLong, Nullable: true, Ref: &Reference{Table: "group", Column: "id"}},
+					{Name: "profile_no", Type: ColTypeLong, UniqueKey: true, Ref: &Reference{Table: "profile", Column: "no"}},
+				},
+			},
+		},
+	}
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Errorf("TestStarUML2_ToSchema() mismatch (-expected +actual):\n%s", diff)
+	}
+}
+
+func TestStarUML2_ToSchema_ReferenceNotFound(t *testing.T) {
+	mdj := `{"_type": "Project", "_id": "p", "ownedElements": [
+{"_type": "ERDDataModel", "_id": "dm", "name": "Data Model", "ownedElements": [` +
+		newStarUML2TestEntity("user",
+			`{"_type": "ERDColumn", "_id": "user.group_id", "_parent": {"$ref": "user"}, "name": "group_id", "type": "BIGINT",
+  "referenceTo": {"$ref": "group.id"}}`,
+		) + `]}]}`
+
+	reader := StarUML2{}
+	if err := reader.FromJson([]byte(mdj)); err != nil {
+		t.Fatal(err)
+	}
+	_, err := reader.ToSchema()
+	expected := "entity: user, column: group_id, referenced column not found: group.id"
+	if err == nil || err.Error() != expected {
+		t.Errorf("TestStarUML2_ToSchema_ReferenceNotFound() expected error %q, got %v", expected, err)
+	}
+}
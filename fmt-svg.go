@@ -0,0 +1,361 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"io/ioutil"
+	"sort"
+	"strings"
+)
+
+const (
+	svgMargin       = 40
+	svgHGap         = 60
+	svgVGap         = 80
+	svgCharWidth    = 8
+	svgRowHeight    = 18
+	svgHeaderHeight = 24
+	svgPadding      = 8
+	svgKeyWidth     = 28
+	svgFontSize     = 12
+)
+
+var svgHeaderColors = []string{
+	"#DDE4F0", "#E2F0DD", "#F0E6DD", "#EADDF0", "#F0DDE2", "#DDF0EE", "#F0EEDD",
+}
+
+type SVG struct {
+	TableFilterFn TableFilterFn
+}
+
+type svgNode struct {
+	table     *Table
+	layer     int
+	order     float64
+	x         int
+	y         int
+	width     int
+	height    int
+	nameWidth int
+	color     string
+}
+
+func (n *svgNode) centerX() int {
+	return n.x + n.width/2
+}
+
+// rowY returns vertical center of the column row.
+func (n *svgNode) rowY(columnName string) int {
+	for i, column := range n.table.Columns {
+		if column.Name == columnName {
+			return n.y + svgHeaderHeight + i*svgRowHeight + svgRowHeight/2
+		}
+	}
+	return n.y + svgHeaderHeight/2
+}
+
+func (f *SVG) FromFile(filename string) error {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+
+	return f.FromString(data)
+}
+
+func (f *SVG) FromString(data []byte) error {
+	// TODO
+	return errors.New("not implemented")
+}
+
+func (f *SVG) ToSchema() (*Schema, error) {
+	// TODO
+	return nil, errors.New("not implemented")
+}
+
+func (f *SVG) ToFile(schema *Schema, filename string) error {
+	data, err := f.ToString(schema)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(filename, data, 0644)
+}
+
+func (f *SVG) ToString(schema *Schema) ([]byte, error) {
+	nodes := make([]*svgNode, 0)
+	nodeByName := make(map[string]*svgNode)
+
+	colorByGroup := make(map[string]string)
+	for i, group := range schema.Groups() {
+		colorByGroup[group] = svgHeaderColors[i%len(svgHeaderColors)]
+	}
+
+	for _, table := range schema.Tables {
+		// filter table
+		if f.TableFilterFn != nil && !f.TableFilterFn(table) {
+			continue
+		}
+		node := &svgNode{
+			table: table,
+			color: colorByGroup[table.Group],
+		}
+		nodes = append(nodes, node)
+		nodeByName[table.Name] = node
+	}
+
+	layers := f.assignLayers(nodes, nodeByName)
+	f.orderLayers(layers, nodeByName)
+	width, height := f.placeNodes(layers)
+
+	result := []string{
+		`<?xml version="1.0" encoding="UTF-8"?>`,
+		fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="monospace" font-size="%d">`,
+			width, height, width, height, svgFontSize),
+		`<defs>`,
+		`<marker id="one" markerWidth="12" markerHeight="14" refX="10" refY="7" orient="auto" markerUnits="userSpaceOnUse"><path d="M4,1 L4,13" stroke="#555555" fill="none"/></marker>`,
+		`<marker id="many" markerWidth="12" markerHeight="14" refX="0" refY="7" orient="auto" markerUnits="userSpaceOnUse"><path d="M0,1 L10,7 M0,13 L10,7 M0,7 L10,7" stroke="#555555" fill="none"/></marker>`,
+		`</defs>`,
+		fmt.Sprintf(`<rect width="%d" height="%d" fill="#FFFFFF"/>`, width, height),
+	}
+
+	// edges
+	for _, node := range nodes {
+		for _, column := range node.table.Columns {
+			if column.Ref == nil {
+				continue
+			}
+			if parent, ok := nodeByName[column.Ref.Table]; ok {
+				result = append(result, f.getEdge(node, column, parent))
+			}
+		}
+	}
+
+	// tables
+	for _, node := range nodes {
+		result = append(result, f.getTable(node)...)
+	}
+
+	result = append(result, "</svg>", "")
+
+	return []byte(strings.Join(result, "\n")), nil
+}
+
+// assignLayers puts referenced tables on upper layers than referencing tables.
+func (f *SVG) assignLayers(nodes []*svgNode, nodeByName map[string]*svgNode) [][]*svgNode {
+	visited := make(map[*svgNode]bool)
+	visiting := make(map[*svgNode]bool)
+
+	var visit func(node *svgNode) int
+	visit = func(node *svgNode) int {
+		if visited[node] {
+			return node.layer
+		}
+		visiting[node] = true
+
+		layer := 0
+		for _, column := range node.table.Columns {
+			if column.Ref == nil {
+				continue
+			}
+			parent, ok := nodeByName[column.Ref.Table]
+			// ignore self reference and cyclic reference
+			if !ok || parent == node || visiting[parent] {
+				continue
+			}
+			if parentLayer := visit(parent) + 1; parentLayer > layer {
+				layer = parentLayer
+			}
+		}
+
+		visiting[node] = false
+		visited[node] = true
+		node.layer = layer
+		return layer
+	}
+
+	layers := make([][]*svgNode, 0)
+	for _, node := range nodes {
+		layer := visit(node)
+		for len(layers) <= layer {
+			layers = append(layers, make([]*svgNode, 0))
+		}
+		layers[layer] = append(layers[layer], node)
+	}
+	return layers
+}
+
+// orderLayers sorts nodes of each layer by barycenter of referenced tables to reduce edge crossings.
+func (f *SVG) orderLayers(layers [][]*svgNode, nodeByName map[string]*svgNode) {
+	for i, layer := range layers {
+		sort.SliceStable(layer, func(a, b int) bool {
+			return TableSlice{layer[a].table, layer[b].table}.Less(0, 1)
+		})
+		for j, node := range layer {
+			node.order = float64(j)
+		}
+		if i == 0 {
+			continue
+		}
+
+		for _, node := range layer {
+			sum, count := 0.0, 0
+			for _, column := range node.table.Columns {
+				if column.Ref == nil {
+					continue
+				}
+				if parent, ok := nodeByName[column.Ref.Table]; ok && parent.layer < node.layer {
+					sum += parent.order
+					count++
+				}
+			}
+			if count > 0 {
+				node.order = sum / float64(count)
+			}
+		}
+		sort.SliceStable(layer, func(a, b int) bool {
+			return layer[a].order < layer[b].order
+		})
+		for j, node := range layer {
+			node.order = float64(j)
+		}
+	}
+}
+
+// placeNodes calculates node sizes and positions. returns total width and height.
+func (f *SVG) placeNodes(layers [][]*svgNode) (int, int) {
+	maxLayerWidth := 0
+	layerWidths := make([]int, len(layers))
+	for i, layer := range layers {
+		for j, node := range layer {
+			nameLen, typeLen := len(node.table.Name), 0
+			for _, column := range node.table.Columns {
+				if l := len(column.Name); l > nameLen {
+					nameLen = l
+				}
+				if l := len(f.getColumnType(column)); l > typeLen {
+					typeLen = l
+				}
+			}
+			node.nameWidth = nameLen * svgCharWidth
+			node.width = svgKeyWidth + node.nameWidth + svgPadding*2 + typeLen*svgCharWidth + svgPadding
+			node.height = svgHeaderHeight + len(node.table.Columns)*svgRowHeight + svgPadding/2
+
+			if j > 0 {
+				layerWidths[i] += svgHGap
+			}
+			layerWidths[i] += node.width
+		}
+		if layerWidths[i] > maxLayerWidth {
+			maxLayerWidth = layerWidths[i]
+		}
+	}
+
+	y := svgMargin
+	for i, layer := range layers {
+		x := svgMargin + (maxLayerWidth-layerWidths[i])/2
+		layerHeight := 0
+		for _, node := range layer {
+			node.x = x
+			node.y = y
+			x += node.width + svgHGap
+			if node.height > layerHeight {
+				layerHeight = node.height
+			}
+		}
+		y += layerHeight + svgVGap
+	}
+
+	return maxLayerWidth + svgMargin*2, y - svgVGap + svgMargin
+}
+
+func (f *SVG) getColumnType(col *Column) string {
+	if col.Size > 0 {
+		if col.Scale > 0 {
+			return fmt.Sprintf("%s(%d,%d)", col.Type, col.Size, col.Scale)
+		}
+		return fmt.Sprintf("%s(%d)", col.Type, col.Size)
+	}
+	return col.Type
+}
+
+func (f *SVG) getTable(node *svgNode) []string {
+	table := node.table
+	x, y := node.x, node.y
+
+	lines := []string{
+		"<g>",
+		fmt.Sprintf("<title>%s</title>", html.EscapeString(TernaryString(table.Description != "", table.Description, table.Name))),
+		fmt.Sprintf(`<rect x="%d" y="%d" width="%d" height="%d" fill="#FFFFFF" stroke="#555555"/>`,
+			x, y, node.width, node.height),
+		fmt.Sprintf(`<rect x="%d" y="%d" width="%d" height="%d" fill="%s" stroke="#555555"/>`,
+			x, y, node.width, svgHeaderHeight, node.color),
+		fmt.Sprintf(`<text x="%d" y="%d" text-anchor="middle" font-weight="bold">%s</text>`,
+			node.centerX(), y+svgHeaderHeight-7, html.EscapeString(table.Name)),
+	}
+
+	for _, column := range table.Columns {
+		rowY := node.rowY(column.Name)
+		keys := make([]string, 0)
+		if column.PrimaryKey {
+			keys = append(keys, "PK")
+		}
+		if column.Ref != nil {
+			keys = append(keys, "FK")
+		}
+		if len(keys) == 0 && column.UniqueKey {
+			keys = append(keys, "UK")
+		}
+
+		nameAttrs := TernaryString(column.Nullable, "", ` font-weight="bold"`)
+		if column.PrimaryKey {
+			nameAttrs += ` text-decoration="underline"`
+		}
+		textY := rowY + svgFontSize/2 - 2
+
+		lines = append(lines, "<g>")
+		if column.Description != "" {
+			lines = append(lines, fmt.Sprintf("<title>%s</title>", html.EscapeString(column.Description)))
+		}
+		lines = append(lines,
+			fmt.Sprintf(`<text x="%d" y="%d" font-size="%d" fill="#777777">%s</text>`,
+				x+svgPadding/2, textY, svgFontSize-2, strings.Join(keys, ",")),
+			fmt.Sprintf(`<text x="%d" y="%d"%s>%s</text>`,
+				x+svgKeyWidth, textY, nameAttrs, html.EscapeString(column.Name)),
+			fmt.Sprintf(`<text x="%d" y="%d" fill="#555555">%s</text>`,
+				x+svgKeyWidth+node.nameWidth+svgPadding*2, textY, html.EscapeString(f.getColumnType(column))),
+			"</g>",
+		)
+	}
+	lines = append(lines, "</g>")
+
+	return lines
+}
+
+// getEdge returns path from foreign key column to referenced column.
+func (f *SVG) getEdge(node *svgNode, column *Column, parent *svgNode) string {
+	const curve = 40
+
+	x1, y1 := node.x+node.width, node.rowY(column.Name)
+	x2, y2 := parent.x+parent.width, parent.rowY(column.Ref.Column)
+	c1, c2 := x1+curve, x2+curve
+
+	if parent != node {
+		switch {
+		case parent.x+parent.width < node.x:
+			// parent is on the left side
+			x1, c1 = node.x, node.x-curve
+			c2 = x2 + curve
+		case node.x+node.width < parent.x:
+			// parent is on the right side
+			x2, c2 = parent.x, parent.x-curve
+		}
+	}
+
+	style := TernaryString(column.Nullable, ` stroke-dasharray="5,3"`, "")
+	startMarker := TernaryString(column.UniqueKey, "url(#one)", "url(#many)")
+
+	return fmt.Sprintf(`<path d="M%d,%d C%d,%d %d,%d %d,%d" fill="none" stroke="#555555"%s marker-start="%s" marker-end="url(#one)"/>`,
+		x1, y1, c1, y1, c2, y2, x2, y2, style, startMarker)
+}
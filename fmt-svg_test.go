@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestSVG_ToString(t *testing.T) {
+	schema := &Schema{
+		Tables: []*Table{
+			{
+				Name: "user",
+				Columns: []*Column{
+					{Name: "id", Type: ColTypeLong, PrimaryKey: true},
+					{Name: "group_id", Type: ColTypeLong, Nullable: true, Ref: &Reference{Table: "group", Column: "id"}},
+					{Name: "parent_id", Type: ColTypeLong, Nullable: true, Ref: &Reference{Table: "user", Column: "id"}},
+				},
+			},
+			{
+				Name:        "group",
+				Description: "<groups>",
+				Columns: []*Column{
+					{Name: "id", Type: ColTypeLong, PrimaryKey: true},
+					{Name: "name", Type: ColTypeString, Size: 40, UniqueKey: true, Description: "a & b"},
+				},
+			},
+		},
+	}
+
+	svg := SVG{}
+	data, err := svg.ToString(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// output should be well-formed
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		if _, err := decoder.Token(); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("TestSVG_ToString() invalid xml: %v\n%s", err, data)
+		}
+	}
+
+	output := string(data)
+	for _, expected := range []string{
+		"<title>&lt;groups&gt;</title>",
+		"<title>a &amp; b</title>",
+		`font-weight="bold" text-decoration="underline">id</text>`,
+		`fill="#555555">string(40)</text>`,
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("TestSVG_ToString() %q not found:\n%s", expected, output)
+		}
+	}
+
+	// nullable reference is dashed, self reference included
+	if count := strings.Count(output, `stroke-dasharray="5,3" marker-start="url(#many)"`); count != 2 {
+		t.Errorf("TestSVG_ToString() expected 2 edges, got %d:\n%s", count, output)
+	}
+}
+
+func TestSVG_placeNodes(t *testing.T) {
+	schema := &Schema{
+		Tables: []*Table{
+			{
+				Name: "user",
+				Columns: []*Column{
+					{Name: "group_id", Type: ColTypeLong, Ref: &Reference{Table: "group", Column: "id"}},
+				},
+			},
+			{
+				Name:    "group",
+				Columns: []*Column{{Name: "id", Type: ColTypeLong, PrimaryKey: true}},
+			},
+			{
+				Name:    "log",
+				Group:   "etc",
+				Columns: []*Column{{Name: "id", Type: ColTypeLong, PrimaryKey: true}},
+			},
+		},
+	}
+
+	svg := SVG{}
+	nodes := make([]*svgNode, 0)
+	nodeByName := make(map[string]*svgNode)
+	for _, table := range schema.Tables {
+		node := &svgNode{table: table}
+		nodes = append(nodes, node)
+		nodeByName[table.Name] = node
+	}
+	layers := svg.assignLayers(nodes, nodeByName)
+	svg.orderLayers(layers, nodeByName)
+	svg.placeNodes(layers)
+
+	// referenced table is placed above referencing table
+	if user, group := nodeByName["user"], nodeByName["group"]; group.y >= user.y {
+		t.Errorf("TestSVG_placeNodes() group.y = %d, user.y = %d", group.y, user.y)
+	}
+	for _, node := range nodes {
+		if node.x < svgMargin || node.y < svgMargin || node.width <= 0 || node.height <= 0 {
+			t.Errorf("TestSVG_placeNodes() invalid node %s: %+v", node.table.Name, node)
+		}
+	}
+}
@@ -34,6 +34,8 @@ func (i *Input) ToSchema() (*Schema, error) {
 	switch i.Format {
 	case FormatOctopus:
 		reader = &Schema{}
+	case FormatLiquibase:
+		reader = &Liquibase{}
 	case FormatStaruml2:
 		reader = &StarUML2{}
 	case FormatXlsx:
@@ -204,6 +206,11 @@ func main() {
 					Usage:  "use 'not null' instead of 'nullable'",
 					EnvVar: "OCTOPUS_NOT_NULL",
 				},
+				cli.StringFlag{
+					Name:   FlagGroups,
+					Usage:  "filter table groups to convert. set multiple values with comma separated.",
+					EnvVar: "OCTOPUS_GROUPS",
+				},
 			},
 			Action: convert,
 		},
@@ -227,6 +234,21 @@ func main() {
 					Usage:  "set target package name",
 					EnvVar: "OCTOPUS_PACKAGE",
 				},
+				cli.StringFlag{
+					Name:   FlagDao,
+					Usage:  "generate Exposed DAO entity classes",
+					EnvVar: "OCTOPUS_DAO",
+				},
+				cli.StringFlag{
+					Name:   FlagLombok,
+					Usage:  "use lombok @Getter/@Setter instead of accessor methods",
+					EnvVar: "OCTOPUS_LOMBOK",
+				},
+				cli.StringFlag{
+					Name:   FlagPersistence,
+					Usage:  "set JPA persistence namespace. javax(default), jakarta",
+					EnvVar: "OCTOPUS_PERSISTENCE",
+				},
 				cli.StringFlag{
 					Name:   FlagReposPackage,
 					Usage:  "set target repository package name",
@@ -234,9 +256,29 @@ func main() {
 				},
 				cli.StringFlag{
 					Name:   FlagRelation,
-					Usage:  "set relation annotation type",
+					Usage:  "set relation annotation type. VRelation, ManyToOne",
 					EnvVar: "OCTOPUS_RELATION",
 				},
+				cli.StringFlag{
+					Name:   FlagFetch,
+					Usage:  "set fetch type of @ManyToOne relation. LAZY(default), EAGER",
+					EnvVar: "OCTOPUS_FETCH",
+				},
+				cli.StringFlag{
+					Name:   FlagCascade,
+					Usage:  "set cascade types of @OneToMany relation. set multiple values with comma separated.",
+					EnvVar: "OCTOPUS_CASCADE",
+				},
+				cli.StringFlag{
+					Name:   FlagOneToMany,
+					Usage:  "generate @OneToMany collections of @ManyToOne relation",
+					EnvVar: "OCTOPUS_ONE_TO_MANY",
+				},
+				cli.StringFlag{
+					Name:   FlagManyToMany,
+					Usage:  "generate @ManyToMany collections instead of join table entities",
+					EnvVar: "OCTOPUS_MANY_TO_MANY",
+				},
 				cli.StringFlag{
 					Name:   FlagIgnoreUnknownRelation,
 					Usage:  "ignore unknown relation",
@@ -252,11 +294,26 @@ func main() {
 					Usage:  "set target graphql package name",
 					EnvVar: "OCTOPUS_GRAPHQL_PACKAGE",
 				},
+				cli.StringFlag{
+					Name:   FlagAudit,
+					Usage:  "set audit columns. e.g. created:created_at,updated:updated_at,deleted:deleted_at,createdBy:created_by,version:version",
+					EnvVar: "OCTOPUS_AUDIT",
+				},
+				cli.StringFlag{
+					Name:   FlagGraphqlFramework,
+					Usage:  "set graphql framework. coxautodev(default), spring, dgs",
+					EnvVar: "OCTOPUS_GRAPHQL_FRAMEWORK",
+				},
 				cli.StringFlag{
 					Name:   FlagGormModel,
 					Usage:  "set embedded base model for GORM model",
 					EnvVar: "OCTOPUS_GORM_MODEL",
 				},
+				cli.StringFlag{
+					Name:   FlagGormVersion,
+					Usage:  "set GORM version. v1(default), v2",
+					EnvVar: "OCTOPUS_GORM_VERSION",
+				},
 				cli.StringFlag{
 					Name:   FlagRemovePrefix,
 					Usage:  "set prefixes to remove. set multiple values with comma separated.",
@@ -277,6 +334,36 @@ func main() {
 					Usage:  "filter table groups to generate. set multiple values with comma separated.",
 					EnvVar: "OCTOPUS_GROUPS",
 				},
+				cli.StringFlag{
+					Name:   FlagChangelogFormat,
+					Usage:  "set liquibase changelog format. yaml(default), xml, json, sql",
+					EnvVar: "OCTOPUS_CHANGELOG_FORMAT",
+				},
+				cli.StringFlag{
+					Name:   FlagChangeSetId,
+					Usage:  "set liquibase changeSet ID strategy. sequence(default), version, timestamp, hash",
+					EnvVar: "OCTOPUS_CHANGE_SET_ID",
+				},
+				cli.StringFlag{
+					Name:   FlagMasterChangelog,
+					Usage:  "append include entry of generated changelog to liquibase master changelog",
+					EnvVar: "OCTOPUS_MASTER_CHANGELOG",
+				},
+				cli.StringFlag{
+					Name:   FlagSplit,
+					Usage:  "split liquibase changelog. table, group",
+					EnvVar: "OCTOPUS_SPLIT",
+				},
+				cli.StringFlag{
+					Name:   FlagDbms,
+					Usage:  "set target dbms. mysql, mariadb, postgresql, sqlite, ...",
+					EnvVar: "OCTOPUS_DBMS",
+				},
+				cli.StringFlag{
+					Name:   FlagTableOptions,
+					Usage:  "set MySQL table options of liquibase createTable",
+					EnvVar: "OCTOPUS_TABLE_OPTIONS",
+				},
 				cli.StringFlag{
 					Name:   FlagDiff,
 					Usage:  "diff octopus filename.",
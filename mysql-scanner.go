@@ -0,0 +1,246 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// statements which do not change schema. their bodies are not buffered.
+var mysqlSkippedKeywordSet = NewStringSet(
+	"insert", "replace", "update", "delete", "lock", "unlock", "set", "use",
+	"start", "begin", "commit", "rollback", "flush", "analyze", "optimize",
+)
+
+// mysqlStatement is a single SQL statement read by mysqlStatementScanner.
+type mysqlStatement struct {
+	Number  int
+	Line    int
+	Keyword string
+	Text    string
+	Skipped bool
+}
+
+// mysqlStatementScanner reads SQL statements one by one from a stream.
+// quotes, comments, MySQL conditional comments('/*!40101 ... */') and 'DELIMITER' commands are handled.
+type mysqlStatementScanner struct {
+	reader    *bufio.Reader
+	line      int
+	number    int
+	delimiter string
+	stmt      *mysqlStatement
+	err       error
+}
+
+func newMysqlStatementScanner(reader io.Reader) *mysqlStatementScanner {
+	return &mysqlStatementScanner{
+		reader:    bufio.NewReaderSize(reader, 64*1024),
+		line:      1,
+		delimiter: ";",
+	}
+}
+
+// Statement returns the statement read by the last Scan call.
+func (s *mysqlStatementScanner) Statement() *mysqlStatement {
+	return s.stmt
+}
+
+// Err returns the first non-EOF error.
+func (s *mysqlStatementScanner) Err() error {
+	return s.err
+}
+
+func (s *mysqlStatementScanner) readRune() (rune, bool) {
+	ch, _, err := s.reader.ReadRune()
+	if err != nil {
+		if err != io.EOF {
+			s.err = err
+		}
+		return 0, false
+	}
+	if ch == '\n' {
+		s.line++
+	}
+	return ch, true
+}
+
+// peekFold returns true if the next bytes match word case-insensitively.
+func (s *mysqlStatementScanner) peekFold(word string) bool {
+	data, _ := s.reader.Peek(len(word))
+	return strings.EqualFold(string(data), word)
+}
+
+// skipLine skips to the end of line and returns the skipped text.
+func (s *mysqlStatementScanner) skipLine() string {
+	var sb strings.Builder
+	for {
+		ch, ok := s.readRune()
+		if !ok || ch == '\n' {
+			return sb.String()
+		}
+		sb.WriteRune(ch)
+	}
+}
+
+// Scan reads the next statement. returns false at the end of input or on error.
+func (s *mysqlStatementScanner) Scan() bool {
+	var buf strings.Builder
+	var keyword strings.Builder
+	keywordDone := false
+	skipped := false
+	started := false
+	startLine := 0
+	conditional := false
+	recent := make([]rune, 0, 8)
+
+	write := func(ch rune) {
+		if !started {
+			if unicode.IsSpace(ch) {
+				return
+			}
+			started = true
+			startLine = s.line
+		}
+		if !keywordDone {
+			if unicode.IsLetter(ch) {
+				keyword.WriteRune(ch)
+			} else {
+				keywordDone = true
+				if mysqlSkippedKeywordSet.Contains(strings.ToLower(keyword.String())) {
+					skipped = true
+					buf.Reset()
+				}
+			}
+		}
+		if !skipped {
+			buf.WriteRune(ch)
+		}
+	}
+
+	// matchDelimiter returns true if recent runes end with delimiter
+	matchDelimiter := func(ch rune) bool {
+		recent = append(recent, ch)
+		if len(recent) > len(s.delimiter) {
+			recent = recent[len(recent)-len(s.delimiter):]
+		}
+		return string(recent) == s.delimiter
+	}
+
+	finish := func() bool {
+		text := strings.TrimSpace(buf.String())
+		if !skipped {
+			text = strings.TrimSpace(strings.TrimSuffix(text, s.delimiter))
+		}
+		s.number++
+		s.stmt = &mysqlStatement{
+			Number:  s.number,
+			Line:    startLine,
+			Keyword: strings.ToLower(keyword.String()),
+			Text:    text,
+			Skipped: skipped,
+		}
+		return true
+	}
+
+	for {
+		// DELIMITER command
+		if !started && s.peekFold("delimiter ") {
+			fields := strings.Fields(s.skipLine())
+			if len(fields) > 1 {
+				s.delimiter = fields[1]
+			}
+			continue
+		}
+
+		ch, ok := s.readRune()
+		if !ok {
+			if started && s.err == nil {
+				return finish()
+			}
+			return false
+		}
+
+		switch {
+		case ch == '\'' || ch == '"' || ch == '`':
+			write(ch)
+			recent = recent[:0]
+			for {
+				c, ok := s.readRune()
+				if !ok {
+					break
+				}
+				write(c)
+				if c == '\\' && ch != '`' {
+					if c, ok = s.readRune(); ok {
+						write(c)
+					}
+					continue
+				}
+				if c == ch {
+					if next, _ := s.reader.Peek(1); len(next) == 1 && rune(next[0]) == ch {
+						c, _ = s.readRune()
+						write(c)
+						continue
+					}
+					break
+				}
+			}
+			continue
+
+		case ch == '#' || (ch == '-' && s.peekFold("- ")) || (ch == '-' && s.peekFold("-\t")) || (ch == '-' && s.peekFold("-\n")):
+			s.skipLine()
+			write('\n')
+			recent = recent[:0]
+			continue
+
+		case ch == '/' && s.peekFold("*!"):
+			// conditional comment: content is a part of statement
+			s.readRune()
+			s.readRune()
+			for {
+				next, _ := s.reader.Peek(1)
+				if len(next) == 0 || next[0] < '0' || next[0] > '9' {
+					break
+				}
+				s.readRune()
+			}
+			conditional = true
+			write(' ')
+			continue
+
+		case ch == '/' && s.peekFold("*"):
+			s.readRune()
+			prev := rune(0)
+			for {
+				c, ok := s.readRune()
+				if !ok || (prev == '*' && c == '/') {
+					break
+				}
+				prev = c
+			}
+			write(' ')
+			continue
+
+		case ch == '*' && conditional && s.peekFold("/"):
+			s.readRune()
+			conditional = false
+			write(' ')
+			continue
+		}
+
+		write(ch)
+		if matchDelimiter(ch) {
+			if !started || strings.TrimSpace(strings.TrimSuffix(buf.String(), s.delimiter)) == "" && !skipped {
+				// empty statement
+				buf.Reset()
+				keyword.Reset()
+				keywordDone = false
+				started = false
+				recent = recent[:0]
+				continue
+			}
+			return finish()
+		}
+	}
+}
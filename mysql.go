@@ -0,0 +1,973 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"github.com/xwb1989/sqlparser"
+	"regexp"
+	"strings"
+)
+
+// column key options of sqlparser.ColumnKeyOption.
+// values are read from parsed statement since the constants of sqlparser are not exported.
+var mysqlColKeyPrimary, mysqlColKeyUnique, mysqlColKeyUniqueKey = mysqlColumnKeyOptions()
+
+func mysqlColumnKeyOptions() (sqlparser.ColumnKeyOption, sqlparser.ColumnKeyOption, sqlparser.ColumnKeyOption) {
+	stmt, err := sqlparser.Parse("create table t (p int primary key, u int unique, uk int unique key)")
+	if err != nil {
+		panic(err)
+	}
+	columns := stmt.(*sqlparser.DDL).TableSpec.Columns
+	return columns[0].Type.KeyOpt, columns[1].Type.KeyOpt, columns[2].Type.KeyOpt
+}
+
+var (
+	mysqlNamePattern        = "(?:`(?:[^`]|``)+`|[\\w$]+)(?:\\s*\\.\\s*(?:`(?:[^`]|``)+`|[\\w$]+))?"
+	mysqlCreateTableRegexp  = regexp.MustCompile(`(?is)^create\s+(?:temporary\s+)?table\s+(if\s+not\s+exists\s+)?(` + mysqlNamePattern + `)\s*(.*)$`)
+	mysqlAlterTableRegexp   = regexp.MustCompile(`(?is)^alter\s+(?:online\s+)?(?:ignore\s+)?table\s+(` + mysqlNamePattern + `)\s*(.*)$`)
+	mysqlTableCommentRegexp = regexp.MustCompile(`(?is)(?:^|\s|,)comment\s*=?\s*'((?:[^'\\]|\\.|'')*)'`)
+	mysqlColumnPosRegexp    = regexp.MustCompile("(?is)\\s+(?:first|after\\s+(?:`(?:[^`]|``)+`|[\\w$]+))\\s*$")
+	mysqlAfterColumnRegexp  = regexp.MustCompile("(?is)\\s+after\\s+(`(?:[^`]|``)+`|[\\w$]+)\\s*$")
+	mysqlAddColumnRegexp    = regexp.MustCompile(`(?is)^add\s+(?:column\s+)?`)
+	mysqlModifyColumnRegexp = regexp.MustCompile(`(?is)^modify\s+(?:column\s+)?`)
+	mysqlChangeColumnRegexp = regexp.MustCompile(`(?is)^change\s+(?:column\s+)?(` + mysqlNamePattern + `)\s+`)
+)
+
+type mysqlToken struct {
+	typ  int
+	text string
+}
+
+// mysqlTokenize splits sql into tokens. comments are skipped.
+func mysqlTokenize(sql string) ([]*mysqlToken, error) {
+	tokenizer := sqlparser.NewStringTokenizer(sql)
+	tokens := make([]*mysqlToken, 0)
+	for {
+		typ, val := tokenizer.Scan()
+		if typ == 0 {
+			break
+		}
+		if typ == sqlparser.LEX_ERROR {
+			return nil, fmt.Errorf("invalid token near '%s'", string(val))
+		}
+		if typ == sqlparser.COMMENT {
+			continue
+		}
+		text := string(val)
+		if val == nil && typ < 256 {
+			text = string(rune(typ))
+		}
+		tokens = append(tokens, &mysqlToken{typ: typ, text: text})
+	}
+	return tokens, nil
+}
+
+// mysqlTokenReader reads tokens sequentially.
+type mysqlTokenReader struct {
+	tokens []*mysqlToken
+	pos    int
+}
+
+func newMysqlTokenReader(sql string) (*mysqlTokenReader, error) {
+	tokens, err := mysqlTokenize(sql)
+	if err != nil {
+		return nil, err
+	}
+	return &mysqlTokenReader{tokens: tokens}, nil
+}
+
+func (r *mysqlTokenReader) done() bool {
+	return r.pos >= len(r.tokens)
+}
+
+// peek returns lowercase text of the current token.
+func (r *mysqlTokenReader) peek() string {
+	if r.done() {
+		return ""
+	}
+	return strings.ToLower(r.tokens[r.pos].text)
+}
+
+func (r *mysqlTokenReader) next() *mysqlToken {
+	if r.done() {
+		return nil
+	}
+	token := r.tokens[r.pos]
+	r.pos++
+	return token
+}
+
+// accept consumes the current token if it matches one of words.
+func (r *mysqlTokenReader) accept(words ...string) bool {
+	current := r.peek()
+	for _, word := range words {
+		if current == word {
+			r.pos++
+			return true
+		}
+	}
+	return false
+}
+
+func (r *mysqlTokenReader) expect(word string) error {
+	if !r.accept(word) {
+		return fmt.Errorf("'%s' expected but '%s' found", word, r.peek())
+	}
+	return nil
+}
+
+// readName reads identifier. database name of qualified name is removed.
+func (r *mysqlTokenReader) readName() (string, error) {
+	token := r.next()
+	if token == nil || token.typ < 256 {
+		return "", errors.New("identifier expected")
+	}
+	name := token.text
+	for r.accept(".") {
+		if token = r.next(); token == nil {
+			return "", errors.New("identifier expected")
+		}
+		name = token.text
+	}
+	return name, nil
+}
+
+// readColumnNames reads column names in parenthesis.
+// prefix length and order of index columns are ignored.
+func (r *mysqlTokenReader) readColumnNames() ([]string, error) {
+	if err := r.expect("("); err != nil {
+		return nil, err
+	}
+	names := make([]string, 0)
+	for {
+		name, err := r.readName()
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+
+		// prefix length
+		if r.accept("(") {
+			for !r.done() && !r.accept(")") {
+				r.next()
+			}
+		}
+		r.accept("asc", "desc")
+
+		if r.accept(")") {
+			return names, nil
+		}
+		if err := r.expect(","); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// readConstraint reads constraint or index definition.
+// returns nil if the current token does not start a constraint definition.
+func (r *mysqlTokenReader) readConstraint() (*tableConstraint, error) {
+	result := &tableConstraint{}
+
+	start := r.pos
+	if r.accept("constraint") {
+		if word := r.peek(); word != "primary" && word != "unique" && word != "foreign" && word != "check" {
+			name, err := r.readName()
+			if err != nil {
+				return nil, err
+			}
+			result.name = name
+		}
+	}
+
+	switch {
+	case r.accept("primary"):
+		if err := r.expect("key"); err != nil {
+			return nil, err
+		}
+		result.primary = true
+	case r.accept("unique"):
+		r.accept("index", "key")
+		result.unique = true
+	case r.accept("fulltext", "spatial"):
+		r.accept("index", "key")
+	case r.accept("index", "key"):
+	case r.accept("foreign"):
+		if err := r.expect("key"); err != nil {
+			return nil, err
+		}
+		result.foreignKey = true
+	case r.accept("check"):
+		// check constraint is ignored
+		r.pos = len(r.tokens)
+		return result, nil
+	default:
+		r.pos = start
+		return nil, nil
+	}
+
+	// index name
+	if word := r.peek(); word != "(" && word != "using" {
+		name, err := r.readName()
+		if err != nil {
+			return nil, err
+		}
+		// constraint symbol is used as foreign key name
+		if result.name == "" || !result.foreignKey {
+			result.name = name
+		}
+	}
+	if r.accept("using") {
+		r.next()
+	}
+
+	columns, err := r.readColumnNames()
+	if err != nil {
+		return nil, err
+	}
+	result.columns = columns
+
+	if result.foreignKey {
+		if err := r.expect("references"); err != nil {
+			return nil, err
+		}
+		refTable, err := r.readName()
+		if err != nil {
+			return nil, err
+		}
+		refColumns, err := r.readColumnNames()
+		if err != nil {
+			return nil, err
+		}
+		if len(refColumns) != len(columns) {
+			return nil, fmt.Errorf("foreign key column count mismatch: %s", refTable)
+		}
+		result.refTable = refTable
+		result.refColumns = refColumns
+	}
+
+	// ignore index options, 'ON DELETE', 'ON UPDATE', etc.
+	r.pos = len(r.tokens)
+
+	return result, nil
+}
+
+// mysqlSplitTopLevel splits s by separator outside of quotes, parenthesis and comments.
+func mysqlSplitTopLevel(s string, separator byte) []string {
+	result := make([]string, 0)
+	depth := 0
+	start := 0
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		switch {
+		case ch == '\'' || ch == '"' || ch == '`':
+			i = mysqlSkipQuoted(s, i)
+		case ch == '-' && i+2 < len(s) && s[i+1] == '-' && (s[i+2] == ' ' || s[i+2] == '\t'),
+			ch == '#':
+			for i < len(s) && s[i] != '\n' {
+				i++
+			}
+		case ch == '/' && i+1 < len(s) && s[i+1] == '*':
+			if end := strings.Index(s[i+2:], "*/"); end >= 0 {
+				i += end + 3
+			} else {
+				i = len(s)
+			}
+		case ch == '(':
+			depth++
+		case ch == ')':
+			depth--
+		case ch == separator && depth == 0:
+			result = append(result, s[start:i])
+			start = i + 1
+		}
+	}
+	if start < len(s) {
+		result = append(result, s[start:])
+	}
+	return result
+}
+
+// mysqlSkipQuoted returns index of the closing quote.
+func mysqlSkipQuoted(s string, start int) int {
+	quote := s[start]
+	for i := start + 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if quote != '`' {
+				i++
+			}
+		case quote:
+			if i+1 < len(s) && s[i+1] == quote {
+				i++
+				continue
+			}
+			return i
+		}
+	}
+	return len(s)
+}
+
+// mysqlFindClosingParen returns index of the closing parenthesis matching s[start].
+func mysqlFindClosingParen(s string, start int) int {
+	depth := 0
+	for i := start; i < len(s); i++ {
+		switch s[i] {
+		case '\'', '"', '`':
+			i = mysqlSkipQuoted(s, i)
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// mysqlUnquoteName removes backquotes and database name from name.
+func mysqlUnquoteName(name string) string {
+	parts := mysqlSplitTopLevel(name, '.')
+	last := strings.TrimSpace(parts[len(parts)-1])
+	if strings.HasPrefix(last, "`") && strings.HasSuffix(last, "`") && len(last) >= 2 {
+		last = strings.ReplaceAll(last[1:len(last)-1], "``", "`")
+	}
+	return last
+}
+
+// mysqlUnescapeString unescapes string literal content.
+func mysqlUnescapeString(s string) string {
+	replacer := strings.NewReplacer(`\'`, `'`, `''`, `'`, `\"`, `"`, `\\`, `\`, `\n`, "\n", `\t`, "\t")
+	return replacer.Replace(s)
+}
+
+// mysqlSchemaBuilder applies DDL statements in order to build a schema.
+type mysqlSchemaBuilder struct {
+	*schemaBuilder
+}
+
+func newMysqlSchemaBuilder() *mysqlSchemaBuilder {
+	return &mysqlSchemaBuilder{newSchemaBuilder()}
+}
+
+// apply applies a single statement to the schema.
+// returns false if statement is not a supported DDL statement.
+func (b *mysqlSchemaBuilder) apply(stmt string) (bool, error) {
+	stmt = strings.TrimSpace(stmt)
+
+	r, err := newMysqlTokenReader(stmt)
+	if err != nil {
+		return false, err
+	}
+
+	switch {
+	case r.accept("create"):
+		if r.accept("database", "schema") {
+			// database options are not part of schema
+			return true, nil
+		}
+		r.accept("temporary")
+		if r.accept("table") {
+			return true, b.applyCreateTable(stmt)
+		}
+		r.accept("unique", "fulltext", "spatial")
+		if r.accept("index") {
+			return true, b.applyCreateIndex(stmt)
+		}
+	case r.accept("alter"):
+		r.accept("online")
+		r.accept("ignore")
+		if r.accept("table") {
+			return true, b.applyAlterTable(stmt)
+		}
+	case r.accept("drop"):
+		if r.accept("database", "schema") {
+			return true, nil
+		}
+		r.accept("temporary")
+		if r.accept("table") {
+			return true, b.applyDropTable(r)
+		}
+		if r.accept("index") {
+			return true, b.applyDropIndex(r)
+		}
+	case r.accept("rename"):
+		if r.accept("table") {
+			return true, b.applyRenameTable(r)
+		}
+	}
+	return false, nil
+}
+
+func (b *mysqlSchemaBuilder) applyCreateTable(stmt string) error {
+	matches := mysqlCreateTableRegexp.FindStringSubmatch(stmt)
+	if matches == nil {
+		return fmt.Errorf("invalid create table statement: %s", stmt)
+	}
+	ifNotExists := matches[1] != ""
+	tableName := mysqlUnquoteName(matches[2])
+	rest := matches[3]
+
+	if _, ok := b.tableByName[tableName]; ok {
+		if ifNotExists {
+			return nil
+		}
+		return fmt.Errorf("table already exists: %s", tableName)
+	}
+
+	// CREATE TABLE ... LIKE
+	if r, err := newMysqlTokenReader(rest); err == nil && r.accept("like") {
+		sourceName, err := r.readName()
+		if err != nil {
+			return err
+		}
+		source, err := b.findTable(sourceName)
+		if err != nil {
+			return err
+		}
+		table := &Table{Name: tableName, Description: source.Description}
+		for _, column := range source.Columns {
+			copied := *column
+			table.AddColumn(&copied)
+		}
+		for _, index := range source.Indices {
+			table.Indices = append(table.Indices, &Index{Name: index.Name, Columns: index.Columns})
+		}
+		b.addTable(table)
+		return nil
+	}
+
+	if !strings.HasPrefix(rest, "(") {
+		return fmt.Errorf("invalid create table statement: %s", stmt)
+	}
+	end := mysqlFindClosingParen(rest, 0)
+	if end < 0 {
+		return fmt.Errorf("invalid create table statement: %s", stmt)
+	}
+	body, options := rest[1:end], rest[end+1:]
+
+	table := &Table{
+		Name:        tableName,
+		Description: b.parseTableComment(options),
+	}
+	columnDefs := make([]string, 0)
+	constraints := make([]*tableConstraint, 0)
+	inlineRefs := make(map[string]*Reference)
+	for _, def := range mysqlSplitTopLevel(body, ',') {
+		def = strings.TrimSpace(def)
+		if def == "" {
+			continue
+		}
+		r, err := newMysqlTokenReader(def)
+		if err != nil {
+			return err
+		}
+		constraint, err := r.readConstraint()
+		if err != nil {
+			return fmt.Errorf("table: %s, %s", tableName, err.Error())
+		}
+		if constraint != nil {
+			constraints = append(constraints, constraint)
+			continue
+		}
+
+		columnDef, ref, err := b.splitInlineReference(def)
+		if err != nil {
+			return fmt.Errorf("table: %s, %s", tableName, err.Error())
+		}
+		columnDefs = append(columnDefs, columnDef)
+		if ref != nil {
+			inlineRefs[columnDef] = ref
+		}
+	}
+
+	columns, err := b.parseColumnDefinitions(columnDefs)
+	if err != nil {
+		return fmt.Errorf("table: %s, %s", tableName, err.Error())
+	}
+	for i, column := range columns {
+		if ref, ok := inlineRefs[columnDefs[i]]; ok {
+			column.Ref = ref
+		}
+		table.AddColumn(column)
+	}
+
+	for _, constraint := range constraints {
+		if err := b.addConstraint(table, constraint); err != nil {
+			// discard index and foreign key names tracked by previous constraints
+			b.removeTable(tableName)
+			return err
+		}
+	}
+
+	// table is added only if it is read completely
+	b.addTable(table)
+	return nil
+}
+
+func (b *mysqlSchemaBuilder) parseTableComment(options string) string {
+	if matches := mysqlTableCommentRegexp.FindStringSubmatch(options); matches != nil {
+		return mysqlUnescapeString(matches[1])
+	}
+	return ""
+}
+
+// splitInlineReference removes 'REFERENCES' clause from column definition.
+func (b *mysqlSchemaBuilder) splitInlineReference(def string) (string, *Reference, error) {
+	r, err := newMysqlTokenReader(def)
+	if err != nil {
+		return "", nil, err
+	}
+	for i, token := range r.tokens {
+		if strings.ToLower(token.text) != "references" || token.typ == sqlparser.STRING || i == 0 {
+			continue
+		}
+		r.pos = i + 1
+		refTable, err := r.readName()
+		if err != nil {
+			return "", nil, err
+		}
+		refColumns, err := r.readColumnNames()
+		if err != nil {
+			return "", nil, err
+		}
+
+		// find 'references' keyword in raw definition
+		loc := regexp.MustCompile(`(?i)\sreferences\s`).FindAllStringIndex(def, -1)
+		if len(loc) == 0 {
+			break
+		}
+		return strings.TrimSpace(def[:loc[len(loc)-1][0]]), &Reference{
+			Table:  refTable,
+			Column: refColumns[0],
+		}, nil
+	}
+	return def, nil, nil
+}
+
+// parseColumnDefinitions parses column definitions using sqlparser.
+func (b *mysqlSchemaBuilder) parseColumnDefinitions(defs []string) ([]*Column, error) {
+	if len(defs) == 0 {
+		return []*Column{}, nil
+	}
+
+	stmt, err := sqlparser.Parse(fmt.Sprintf("create table t (\n%s\n)", strings.Join(defs, ",\n")))
+	if err != nil {
+		// find invalid column definition
+		for _, def := range defs {
+			if _, e := sqlparser.Parse(fmt.Sprintf("create table t (%s)", def)); e != nil {
+				return nil, fmt.Errorf("invalid column definition: %s, %s", def, e.Error())
+			}
+		}
+		return nil, err
+	}
+
+	ddl, ok := stmt.(*sqlparser.DDL)
+	if !ok || ddl.TableSpec == nil {
+		return nil, fmt.Errorf("invalid column definitions: %s", strings.Join(defs, ", "))
+	}
+
+	columns := make([]*Column, 0)
+	for _, col := range ddl.TableSpec.Columns {
+		columns = append(columns, b.toColumn(col))
+	}
+	return columns, nil
+}
+
+func (b *mysqlSchemaBuilder) toColumn(col *sqlparser.ColumnDefinition) *Column {
+	nullable := !bool(col.Type.NotNull)
+	defaultValue := SQLValToString(col.Type.Default, "")
+	if nullable && defaultValue == "null" {
+		defaultValue = ""
+	}
+	keyOpt := col.Type.KeyOpt
+	return &Column{
+		Name:            col.Name.String(),
+		Type:            (&Mysql{}).fromColumnType(col.Type),
+		Description:     SQLValToString(col.Type.Comment, ""),
+		Size:            uint16(SQLValToInt(col.Type.Length, 0)),
+		Scale:           uint16(SQLValToInt(col.Type.Scale, 0)),
+		Nullable:        nullable && keyOpt != mysqlColKeyPrimary,
+		PrimaryKey:      keyOpt == mysqlColKeyPrimary,
+		UniqueKey:       keyOpt == mysqlColKeyUnique || keyOpt == mysqlColKeyUniqueKey,
+		AutoIncremental: bool(col.Type.Autoincrement),
+		DefaultValue:    defaultValue,
+	}
+}
+
+func (b *mysqlSchemaBuilder) parseColumnDefinition(def string) (*Column, error) {
+	columnDef, ref, err := b.splitInlineReference(def)
+	if err != nil {
+		return nil, err
+	}
+	columns, err := b.parseColumnDefinitions([]string{columnDef})
+	if err != nil {
+		return nil, err
+	}
+	columns[0].Ref = ref
+	return columns[0], nil
+}
+
+func (b *mysqlSchemaBuilder) applyCreateIndex(stmt string) error {
+	r, err := newMysqlTokenReader(stmt)
+	if err != nil {
+		return err
+	}
+	r.accept("create")
+	unique := r.accept("unique")
+	r.accept("fulltext", "spatial")
+	if err := r.expect("index"); err != nil {
+		return err
+	}
+	indexName, err := r.readName()
+	if err != nil {
+		return err
+	}
+	if r.accept("using") {
+		r.next()
+	}
+	if err := r.expect("on"); err != nil {
+		return err
+	}
+	tableName, err := r.readName()
+	if err != nil {
+		return err
+	}
+	columns, err := r.readColumnNames()
+	if err != nil {
+		return err
+	}
+
+	table, err := b.findTable(tableName)
+	if err != nil {
+		return err
+	}
+	return b.addConstraint(table, &tableConstraint{
+		name:    indexName,
+		columns: columns,
+		unique:  unique,
+	})
+}
+
+func (b *mysqlSchemaBuilder) applyDropTable(r *mysqlTokenReader) error {
+	ifExists := false
+	if r.accept("if") {
+		if err := r.expect("exists"); err != nil {
+			return err
+		}
+		ifExists = true
+	}
+	for {
+		name, err := r.readName()
+		if err != nil {
+			return err
+		}
+		if _, ok := b.tableByName[name]; ok {
+			b.removeTable(name)
+		} else if !ifExists {
+			return fmt.Errorf("table not found: %s", name)
+		}
+		if !r.accept(",") {
+			return nil
+		}
+	}
+}
+
+func (b *mysqlSchemaBuilder) applyDropIndex(r *mysqlTokenReader) error {
+	indexName, err := r.readName()
+	if err != nil {
+		return err
+	}
+	if err := r.expect("on"); err != nil {
+		return err
+	}
+	tableName, err := r.readName()
+	if err != nil {
+		return err
+	}
+	table, err := b.findTable(tableName)
+	if err != nil {
+		return err
+	}
+	return b.dropIndex(table, indexName)
+}
+
+func (b *mysqlSchemaBuilder) applyRenameTable(r *mysqlTokenReader) error {
+	for {
+		oldName, err := r.readName()
+		if err != nil {
+			return err
+		}
+		if err := r.expect("to"); err != nil {
+			return err
+		}
+		newName, err := r.readName()
+		if err != nil {
+			return err
+		}
+		if err := b.renameTable(oldName, newName); err != nil {
+			return err
+		}
+		if !r.accept(",") {
+			return nil
+		}
+	}
+}
+
+func (b *mysqlSchemaBuilder) applyAlterTable(stmt string) error {
+	matches := mysqlAlterTableRegexp.FindStringSubmatch(stmt)
+	if matches == nil {
+		return fmt.Errorf("invalid alter table statement: %s", stmt)
+	}
+	table, err := b.findTable(mysqlUnquoteName(matches[1]))
+	if err != nil {
+		return err
+	}
+
+	for _, spec := range mysqlSplitTopLevel(matches[2], ',') {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+		if err := b.applyAlterSpec(table, spec); err != nil {
+			return fmt.Errorf("table: %s, %s", table.Name, err.Error())
+		}
+	}
+	return nil
+}
+
+// applyAlterSpec applies single alter specification.
+// unsupported specifications such as table options and partitioning are ignored.
+func (b *mysqlSchemaBuilder) applyAlterSpec(table *Table, spec string) error {
+	r, err := newMysqlTokenReader(spec)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case r.accept("add"):
+		hasColumnKeyword := r.accept("column")
+		if !hasColumnKeyword {
+			if constraint, err := r.readConstraint(); err != nil {
+				return err
+			} else if constraint != nil {
+				return b.addConstraint(table, constraint)
+			}
+		}
+		def := strings.TrimSpace(mysqlAddColumnRegexp.ReplaceAllString(spec, ""))
+		if strings.HasPrefix(def, "(") {
+			// ADD (col1 ..., col2 ...)
+			end := mysqlFindClosingParen(def, 0)
+			if end < 0 {
+				return fmt.Errorf("invalid column definition: %s", def)
+			}
+			for _, columnDef := range mysqlSplitTopLevel(def[1:end], ',') {
+				if err := b.addColumn(table, strings.TrimSpace(columnDef)); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		return b.addColumn(table, def)
+
+	case r.accept("modify"):
+		def := strings.TrimSpace(mysqlModifyColumnRegexp.ReplaceAllString(spec, ""))
+		r.accept("column")
+		name, err := r.readName()
+		if err != nil {
+			return err
+		}
+		return b.replaceColumn(table, name, def)
+
+	case r.accept("change"):
+		matches := mysqlChangeColumnRegexp.FindStringSubmatch(spec)
+		if matches == nil {
+			return fmt.Errorf("invalid change column: %s", spec)
+		}
+		def := strings.TrimSpace(spec[len(matches[0]):])
+		return b.replaceColumn(table, mysqlUnquoteName(matches[1]), def)
+
+	case r.accept("drop"):
+		switch {
+		case r.accept("primary"):
+			b.dropPrimaryKey(table)
+			return nil
+		case r.accept("index", "key"):
+			name, err := r.readName()
+			if err != nil {
+				return err
+			}
+			return b.dropIndex(table, name)
+		case r.accept("foreign"):
+			if err := r.expect("key"); err != nil {
+				return err
+			}
+			name, err := r.readName()
+			if err != nil {
+				return err
+			}
+			return b.dropForeignKey(table, name)
+		case r.accept("constraint"):
+			// DROP CONSTRAINT of MySQL 8.0.19+ drops foreign key, unique key or check constraint
+			name, err := r.readName()
+			if err != nil {
+				return err
+			}
+			if _, ok := b.foreignKeys[table.Name][name]; ok {
+				return b.dropForeignKey(table, name)
+			}
+			if _, ok := b.uniqueIndexes[table.Name][name]; ok {
+				return b.dropIndex(table, name)
+			}
+			return nil
+		case r.accept("check", "partition"):
+			return nil
+		}
+		r.accept("column")
+		name, err := r.readName()
+		if err != nil {
+			return err
+		}
+		return b.dropColumn(table, name)
+
+	case r.accept("alter"):
+		r.accept("column")
+		name, err := r.readName()
+		if err != nil {
+			return err
+		}
+		column, ok := table.ColumnByName()[name]
+		if !ok {
+			return fmt.Errorf("column not found: %s", name)
+		}
+		if r.accept("set") {
+			if err := r.expect("default"); err != nil {
+				return err
+			}
+			if token := r.next(); token != nil {
+				column.DefaultValue = token.text
+				if strings.ToLower(token.text) == "null" {
+					column.DefaultValue = ""
+				}
+			}
+		} else if r.accept("drop") {
+			column.DefaultValue = ""
+		}
+		return nil
+
+	case r.accept("rename"):
+		switch {
+		case r.accept("column"):
+			oldName, err := r.readName()
+			if err != nil {
+				return err
+			}
+			if err := r.expect("to"); err != nil {
+				return err
+			}
+			newName, err := r.readName()
+			if err != nil {
+				return err
+			}
+			return b.renameColumn(table, oldName, newName)
+		case r.accept("index", "key"):
+			oldName, err := r.readName()
+			if err != nil {
+				return err
+			}
+			if err := r.expect("to"); err != nil {
+				return err
+			}
+			newName, err := r.readName()
+			if err != nil {
+				return err
+			}
+			b.renameIndex(table, oldName, newName)
+			return nil
+		}
+		r.accept("to", "as")
+		newName, err := r.readName()
+		if err != nil {
+			return err
+		}
+		return b.renameTable(table.Name, newName)
+
+	case r.peek() == "comment":
+		table.Description = b.parseTableComment(spec)
+		return nil
+	}
+
+	// table options
+	if comment := b.parseTableComment(spec); comment != "" {
+		table.Description = comment
+	}
+	return nil
+}
+
+// addColumn adds column. 'FIRST', 'AFTER' positions are supported.
+func (b *mysqlSchemaBuilder) addColumn(table *Table, def string) error {
+	columnDef, first, after := b.splitColumnPosition(def)
+	column, err := b.parseColumnDefinition(columnDef)
+	if err != nil {
+		return err
+	}
+	if _, ok := table.ColumnByName()[column.Name]; ok {
+		return fmt.Errorf("duplicate column: %s", column.Name)
+	}
+	b.insertColumn(table, column, first, after)
+	return nil
+}
+
+// replaceColumn replaces column definition. keys and references of old column are kept.
+func (b *mysqlSchemaBuilder) replaceColumn(table *Table, oldName string, def string) error {
+	columnDef, first, after := b.splitColumnPosition(def)
+	column, err := b.parseColumnDefinition(columnDef)
+	if err != nil {
+		return err
+	}
+
+	index := -1
+	for i, c := range table.Columns {
+		if c.Name == oldName {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return fmt.Errorf("column not found: %s", oldName)
+	}
+
+	oldColumn := table.Columns[index]
+	column.PrimaryKey = column.PrimaryKey || oldColumn.PrimaryKey
+	column.UniqueKey = column.UniqueKey || oldColumn.UniqueKey
+	if column.PrimaryKey {
+		column.Nullable = false
+	}
+	if column.Ref == nil {
+		column.Ref = oldColumn.Ref
+	}
+
+	if first || after != "" {
+		table.Columns = append(table.Columns[:index], table.Columns[index+1:]...)
+		b.insertColumn(table, column, first, after)
+	} else {
+		table.Columns[index] = column
+	}
+
+	if column.Name != oldName {
+		b.updateColumnName(table, oldName, column.Name)
+	}
+	return nil
+}
+
+func (b *mysqlSchemaBuilder) splitColumnPosition(def string) (string, bool, string) {
+	loc := mysqlColumnPosRegexp.FindStringIndex(def)
+	if loc == nil {
+		return def, false, ""
+	}
+	if matches := mysqlAfterColumnRegexp.FindStringSubmatch(def); matches != nil {
+		return def[:loc[0]], false, mysqlUnquoteName(matches[1])
+	}
+	return def[:loc[0]], true, ""
+}
@@ -0,0 +1,328 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// tableConstraint is a primary key, unique key, index or foreign key definition.
+type tableConstraint struct {
+	name       string
+	columns    []string
+	primary    bool
+	unique     bool
+	foreignKey bool
+	refTable   string
+	refColumns []string
+}
+
+// schemaBuilder builds a schema by applying changes in order.
+// names of unique indexes and foreign keys are tracked to support dropping them by name.
+type schemaBuilder struct {
+	tables        []*Table
+	tableByName   map[string]*Table
+	uniqueIndexes map[string]map[string][]string
+	foreignKeys   map[string]map[string][]string
+}
+
+func newSchemaBuilder() *schemaBuilder {
+	return &schemaBuilder{
+		tables:        make([]*Table, 0),
+		tableByName:   make(map[string]*Table),
+		uniqueIndexes: make(map[string]map[string][]string),
+		foreignKeys:   make(map[string]map[string][]string),
+	}
+}
+
+func (b *schemaBuilder) schema() *Schema {
+	return &Schema{
+		Tables: b.tables,
+	}
+}
+
+func (b *schemaBuilder) findTable(name string) (*Table, error) {
+	if table, ok := b.tableByName[name]; ok {
+		return table, nil
+	}
+	return nil, fmt.Errorf("table not found: %s", name)
+}
+
+func (b *schemaBuilder) addTable(table *Table) {
+	b.tables = append(b.tables, table)
+	b.tableByName[table.Name] = table
+}
+
+// addConstraint applies primary key, unique key, index or foreign key to table.
+func (b *schemaBuilder) addConstraint(table *Table, constraint *tableConstraint) error {
+	columnByName := table.ColumnByName()
+	for _, name := range constraint.columns {
+		if _, ok := columnByName[name]; !ok {
+			return fmt.Errorf("table: %s, column not found: %s", table.Name, name)
+		}
+	}
+
+	switch {
+	case constraint.primary:
+		for _, name := range constraint.columns {
+			columnByName[name].PrimaryKey = true
+			columnByName[name].Nullable = false
+		}
+	case constraint.unique:
+		name := b.indexName(table, constraint)
+		if b.uniqueIndexes[table.Name] == nil {
+			b.uniqueIndexes[table.Name] = make(map[string][]string)
+		}
+		b.uniqueIndexes[table.Name][name] = constraint.columns
+		for _, name := range constraint.columns {
+			columnByName[name].UniqueKey = true
+		}
+	case constraint.foreignKey:
+		name := constraint.name
+		if name == "" {
+			name = fmt.Sprintf("%s_ibfk_%d", table.Name, len(b.foreignKeys[table.Name])+1)
+		}
+		if b.foreignKeys[table.Name] == nil {
+			b.foreignKeys[table.Name] = make(map[string][]string)
+		}
+		b.foreignKeys[table.Name][name] = constraint.columns
+		for i, name := range constraint.columns {
+			columnByName[name].Ref = &Reference{
+				Table:  constraint.refTable,
+				Column: constraint.refColumns[i],
+			}
+		}
+	default:
+		table.Indices = append(table.Indices, &Index{
+			Name:    b.indexName(table, constraint),
+			Columns: constraint.columns,
+		})
+	}
+	return nil
+}
+
+// indexName returns index name. first column name is used if name is not set.
+func (b *schemaBuilder) indexName(table *Table, constraint *tableConstraint) string {
+	if constraint.name != "" {
+		return constraint.name
+	}
+	nameSet := NewStringSet()
+	for _, index := range table.Indices {
+		nameSet.Add(index.Name)
+	}
+	for name := range b.uniqueIndexes[table.Name] {
+		nameSet.Add(name)
+	}
+	name := constraint.columns[0]
+	for i := 2; nameSet.Contains(name); i++ {
+		name = fmt.Sprintf("%s_%d", constraint.columns[0], i)
+	}
+	return name
+}
+
+func (b *schemaBuilder) removeTable(name string) {
+	tables := make([]*Table, 0, len(b.tables))
+	for _, table := range b.tables {
+		if table.Name != name {
+			tables = append(tables, table)
+		}
+	}
+	b.tables = tables
+	delete(b.tableByName, name)
+	delete(b.uniqueIndexes, name)
+	delete(b.foreignKeys, name)
+}
+
+func (b *schemaBuilder) dropIndex(table *Table, indexName string) error {
+	if strings.ToLower(indexName) == "primary" {
+		b.dropPrimaryKey(table)
+		return nil
+	}
+
+	if columns, ok := b.uniqueIndexes[table.Name][indexName]; ok {
+		delete(b.uniqueIndexes[table.Name], indexName)
+
+		// columns in other unique indexes remain unique
+		uniqueColumnSet := NewStringSet()
+		for _, names := range b.uniqueIndexes[table.Name] {
+			uniqueColumnSet.AddAll(names)
+		}
+		columnByName := table.ColumnByName()
+		for _, name := range columns {
+			if column, ok := columnByName[name]; ok && !uniqueColumnSet.Contains(name) {
+				column.UniqueKey = false
+			}
+		}
+		return nil
+	}
+
+	for i, index := range table.Indices {
+		if index.Name == indexName {
+			table.Indices = append(table.Indices[:i], table.Indices[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("table: %s, index not found: %s", table.Name, indexName)
+}
+
+func (b *schemaBuilder) dropPrimaryKey(table *Table) {
+	for _, column := range table.Columns {
+		column.PrimaryKey = false
+	}
+}
+
+func (b *schemaBuilder) renameTable(oldName string, newName string) error {
+	table, err := b.findTable(oldName)
+	if err != nil {
+		return err
+	}
+	table.Name = newName
+	delete(b.tableByName, oldName)
+	b.tableByName[newName] = table
+
+	b.uniqueIndexes[newName] = b.uniqueIndexes[oldName]
+	delete(b.uniqueIndexes, oldName)
+	b.foreignKeys[newName] = b.foreignKeys[oldName]
+	delete(b.foreignKeys, oldName)
+
+	// update references
+	for _, t := range b.tables {
+		for _, column := range t.Columns {
+			if column.Ref != nil && column.Ref.Table == oldName {
+				column.Ref.Table = newName
+			}
+		}
+	}
+	return nil
+}
+
+func (b *schemaBuilder) insertColumn(table *Table, column *Column, first bool, after string) {
+	index := len(table.Columns)
+	if first {
+		index = 0
+	} else if after != "" {
+		for i, c := range table.Columns {
+			if c.Name == after {
+				index = i + 1
+				break
+			}
+		}
+	}
+	columns := make([]*Column, 0, len(table.Columns)+1)
+	columns = append(columns, table.Columns[:index]...)
+	columns = append(columns, column)
+	columns = append(columns, table.Columns[index:]...)
+	table.Columns = columns
+}
+
+func (b *schemaBuilder) dropColumn(table *Table, name string) error {
+	index := -1
+	for i, c := range table.Columns {
+		if c.Name == name {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return fmt.Errorf("column not found: %s", name)
+	}
+	table.Columns = append(table.Columns[:index], table.Columns[index+1:]...)
+
+	// remove column from indexes
+	removeName := func(names []string) []string {
+		result := make([]string, 0, len(names))
+		for _, n := range names {
+			if n != name {
+				result = append(result, n)
+			}
+		}
+		return result
+	}
+	var indices []*Index
+	for _, index := range table.Indices {
+		if index.Columns = removeName(index.Columns); len(index.Columns) > 0 {
+			indices = append(indices, index)
+		}
+	}
+	table.Indices = indices
+	for indexName, names := range b.uniqueIndexes[table.Name] {
+		if names = removeName(names); len(names) > 0 {
+			b.uniqueIndexes[table.Name][indexName] = names
+		} else {
+			delete(b.uniqueIndexes[table.Name], indexName)
+		}
+	}
+	for fkName, names := range b.foreignKeys[table.Name] {
+		if names = removeName(names); len(names) > 0 {
+			b.foreignKeys[table.Name][fkName] = names
+		} else {
+			delete(b.foreignKeys[table.Name], fkName)
+		}
+	}
+	return nil
+}
+
+func (b *schemaBuilder) renameColumn(table *Table, oldName string, newName string) error {
+	column, ok := table.ColumnByName()[oldName]
+	if !ok {
+		return fmt.Errorf("column not found: %s", oldName)
+	}
+	column.Name = newName
+	b.updateColumnName(table, oldName, newName)
+	return nil
+}
+
+// updateColumnName updates column name of indexes and references.
+func (b *schemaBuilder) updateColumnName(table *Table, oldName string, newName string) {
+	rename := func(names []string) {
+		for i, n := range names {
+			if n == oldName {
+				names[i] = newName
+			}
+		}
+	}
+	for _, index := range table.Indices {
+		rename(index.Columns)
+	}
+	for _, names := range b.uniqueIndexes[table.Name] {
+		rename(names)
+	}
+	for _, names := range b.foreignKeys[table.Name] {
+		rename(names)
+	}
+	for _, t := range b.tables {
+		for _, column := range t.Columns {
+			if ref := column.Ref; ref != nil && ref.Table == table.Name && ref.Column == oldName {
+				ref.Column = newName
+			}
+		}
+	}
+}
+
+func (b *schemaBuilder) renameIndex(table *Table, oldName string, newName string) {
+	if columns, ok := b.uniqueIndexes[table.Name][oldName]; ok {
+		delete(b.uniqueIndexes[table.Name], oldName)
+		b.uniqueIndexes[table.Name][newName] = columns
+		return
+	}
+	for _, index := range table.Indices {
+		if index.Name == oldName {
+			index.Name = newName
+		}
+	}
+}
+
+func (b *schemaBuilder) dropForeignKey(table *Table, name string) error {
+	columns, ok := b.foreignKeys[table.Name][name]
+	if !ok {
+		return fmt.Errorf("foreign key not found: %s", name)
+	}
+	delete(b.foreignKeys[table.Name], name)
+
+	columnByName := table.ColumnByName()
+	for _, columnName := range columns {
+		if column, ok := columnByName[columnName]; ok {
+			column.Ref = nil
+		}
+	}
+	return nil
+}
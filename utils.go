@@ -23,6 +23,9 @@ func ToInt(value interface{}, defaultValue int) int {
 		return i
 	case int:
 		return value.(int)
+	case float64:
+		// json numbers are decoded as float64
+		return int(value.(float64))
 	default:
 		return defaultValue
 	}
@@ -124,10 +127,20 @@ func GetFileFormat(fileFormat string, filename string) string {
 
 	ext := filepath.Ext(filename)
 	switch strings.ToLower(ext) {
+	case ".dot":
+		fallthrough
+	case ".gv":
+		return FormatDot
+	case ".drawio":
+		return FormatDrawio
 	case ".graphql":
 		fallthrough
 	case ".graphqls":
 		return FormatGraphql
+	case ".mmd":
+		fallthrough
+	case ".mermaid":
+		return FormatMermaid
 	case ".mdj":
 		return FormatStaruml2
 	case ".ojson":
@@ -136,8 +149,14 @@ func GetFileFormat(fileFormat string, filename string) string {
 		return FormatPlantuml
 	case ".schema":
 		return FormatSchemaConverter
+	case ".svg":
+		return FormatSvg
 	case ".xlsx":
 		return FormatXlsx
+	case ".yaml":
+		fallthrough
+	case ".yml":
+		return FormatLiquibase
 	default:
 		return ""
 	}